@@ -0,0 +1,222 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chains-project/ghasum/internal/cache"
+	"github.com/chains-project/ghasum/internal/ghasum"
+	"github.com/chains-project/ghasum/internal/github"
+)
+
+func cmdAudit(argv []string) error {
+	var (
+		flags                  = flag.NewFlagSet(cmdNameAudit, flag.ContinueOnError)
+		flagCache              = flags.String(flagNameCache, "", "")
+		flagDB                 = flags.String(flagNameDB, "", "")
+		flagDebug              = flags.Bool(flagNameDebug, false, "")
+		flagGitHubURL          = flags.String(flagNameGitHubURL, "", "")
+		flagNoEvict            = flags.Bool(flagNameNoEvict, false, "")
+		flagOffline            = flags.Bool(flagNameOffline, false, "")
+		flagRev                = flags.String(flagNameRev, "", "")
+		flagRewrite            = make(rewrites)
+		flagSumfile            = flags.String(flagNameSumfile, "", "")
+		flagSumfilePerWorkflow = flags.Bool(flagNameSumfilePerWorkflow, false, "")
+		flagVerbose            = flags.Bool(flagNameVerbose, false, "")
+	)
+
+	flags.Var(flagRewrite, flagNameRewrite, "")
+	flags.Usage = func() { fmt.Fprintln(os.Stderr) }
+	if err := flags.Parse(argv); err != nil {
+		return errUsage
+	}
+
+	setVerbosity(*flagVerbose, *flagDebug)
+
+	args := flags.Args()
+	if len(args) < 1 || len(args) > 2 {
+		return errUsage
+	}
+
+	check := args[0]
+	target, err := getTarget(args[1:])
+	if err != nil {
+		return err
+	}
+
+	sumfile, err := getSumfile(*flagSumfile, *flagSumfilePerWorkflow)
+	if err != nil {
+		return err
+	}
+
+	repo, err := getRepo(target, *flagRev)
+	if err != nil {
+		return err
+	}
+
+	if _, err = configure(flags, repo); err != nil {
+		return err
+	}
+
+	host, api, err := getGitHub(*flagGitHubURL)
+	if err != nil {
+		return err
+	}
+
+	cfg := ghasum.Config{
+		Repo:               repo,
+		Path:               target,
+		Sumfile:            sumfile,
+		SumfilePerWorkflow: *flagSumfilePerWorkflow,
+		Offline:            *flagOffline,
+		Transitive:         true,
+		Host:               host,
+		API:                api,
+		Rewrites:           github.Rewrites(flagRewrite),
+		Progress:           getProgress(),
+	}
+
+	var (
+		report  ghasum.AuditReport
+		subject string
+	)
+
+	switch check {
+	case "tags":
+		report, err = ghasum.AuditTags(&cfg)
+		subject = check
+	case "vulns":
+		if *flagDB == "" {
+			return fmt.Errorf("the -%s flag is required for the %q check", flagNameDB, check)
+		}
+
+		cfg.Advisories, err = os.ReadFile(*flagDB)
+		if err != nil {
+			return errors.Join(errUnexpected, err)
+		}
+
+		cfg.Cache, err = cache.New(
+			cache.WithLocation(*flagCache),
+			cache.WithEviction(!*flagNoEvict),
+		)
+		if err != nil {
+			return errors.Join(errCache, err)
+		}
+
+		report, err = ghasum.AuditVulns(&cfg)
+		subject = "actions"
+	default:
+		return fmt.Errorf(`unknown check %q (see "ghasum help audit")`, check)
+	}
+
+	if err != nil {
+		return errors.Join(errUnexpected, err)
+	}
+
+	return reportAudit(subject, &report)
+}
+
+func reportAudit(subject string, report *ghasum.AuditReport) error {
+	if cnt := len(report.Warnings); cnt > 0 {
+		fmt.Printf("%d warning(s) occurred during the audit:\n", cnt)
+		for _, warning := range report.Warnings {
+			fmt.Println("  " + warning)
+		}
+	}
+
+	if cnt := len(report.Problems); cnt > 0 {
+		var sb strings.Builder
+
+		sb.WriteString(fmt.Sprintf("%d problem(s) found during the audit:\n", cnt))
+		for _, problem := range report.Problems {
+			sb.WriteString("  " + string(problem) + "\n")
+		}
+
+		return errors.Join(errFailure, errors.New(sb.String()))
+	}
+
+	fmt.Printf("Ok (audited %d %s)\n", report.Total, subject)
+	return nil
+}
+
+func helpAudit() string {
+	return `usage: ghasum audit [flags] <check> [target]
+
+Audit the Actions used by the target using the given check. If no target is
+provided it will default to the current working directory. If the audit finds
+problems this command will error with a non-zero exit code.
+
+The available checks are:
+
+    tags    Check that the tags used by Actions still point to the commit for
+            which the checksum was computed, i.e. that the tags did not move.
+            Only the tags of repositories are looked up, nothing is cloned.
+            Audits the Actions recorded in the gha.sum file, which must record
+            commits, which is done by ghasum from sumfile version 3 onwards.
+
+    vulns   Check all Actions in the target, including transitive Actions,
+            against the security advisories in a local copy of the OSV
+            database given by -db. The version of an Action is derived from
+            its ref, or from tags with the same commit in the cache if the ref
+            is not a semantic version (e.g. v1 or a commit SHA).
+
+The available flags are:
+
+    -cache dir
+        The location of the cache directory. This is where ghasum stores and
+        looks up repositories it needs. Only used by the vulns check.
+        Defaults to $XDG_CACHE_HOME/ghasum, or a directory named .ghasum in the
+        user's home directory if it is unset.
+    -db path
+        The path to a zip archive of advisories in the OSV format for the
+        GitHub Actions ecosystem, e.g. as downloaded from
+        https://osv-vulnerabilities.storage.googleapis.com/GitHub%20Actions/all.zip
+        Required for and only used by the vulns check.
+    -debug
+        Log debug messages to stderr in addition to those of -v, such as every
+        attempt to fetch a repository.
+    -github-url url
+        The URL of the GitHub instance, e.g. a GitHub Enterprise Server, that
+        houses actions used without an explicit host.
+        Defaults to $GITHUB_SERVER_URL, or https://github.com if it is unset.
+    -no-evict
+        Disable cache eviction.
+    -offline
+        Run without fetching repositories from the internet. If the cache is
+        missing an entry it causes an error. Only used by the vulns check.
+    -rev rev
+        Use the target at the given git revision (e.g. a branch, tag, or commit
+        SHA) instead of its working tree. The target must be a git repository.
+    -rewrite from=to
+        Use the URL prefix to instead of from for repositories, in the style of
+        git's url.<base>.insteadOf (e.g. to use an internal mirror). The longest
+        matching prefix is used. Can be used multiple times.
+    -sumfile path
+        The path to the gha.sum file, relative to the target.
+        Defaults to .github/workflows/gha.sum.
+    -sumfile-per-workflow
+        Use a separate checksum file for every workflow, located next to the
+        workflow it belongs to (e.g. ci.yml.sum for ci.yml). Cannot be used
+        together with -sumfile.
+    -v
+        Log what is being done to stderr, such as which actions are fetched and
+        checksummed and how long that took.
+`
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"testing"
+
+	"github.com/rogpeppe/go-internal/testscript"
+)
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	params := testscript.Params{
+		Dir: "../../testdata/audit",
+		Cmds: map[string]func(ts *testscript.TestScript, neg bool, args []string){
+			"zip": cmdZip,
+		},
+	}
+
+	testscript.Run(t, params)
+}
+
+// cmdZip creates a zip archive (the first argument) of files (the remaining
+// arguments), for use in test scripts as "zip archive.zip file...".
+func cmdZip(ts *testscript.TestScript, neg bool, args []string) {
+	if neg || len(args) < 2 {
+		ts.Fatalf("usage: zip archive.zip file...")
+	}
+
+	out, err := os.Create(ts.MkAbs(args[0]))
+	ts.Check(err)
+
+	w := zip.NewWriter(out)
+	for _, file := range args[1:] {
+		f, err := w.Create(file)
+		ts.Check(err)
+
+		_, err = f.Write([]byte(ts.ReadFile(file)))
+		ts.Check(err)
+	}
+
+	ts.Check(w.Close())
+	ts.Check(out.Close())
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"github.com/chains-project/ghasum/internal/cache"
+	"github.com/chains-project/ghasum/internal/ghasum"
 )
 
 func cmdCache(argv []string) error {
@@ -41,6 +42,10 @@ func cmdCache(argv []string) error {
 		return errors.New("only one command can be run at the time")
 	}
 
+	if _, err := configure(flags, nil); err != nil {
+		return err
+	}
+
 	c, err := cache.New(
 		cache.WithLocation(*flagCache),
 		cache.WithEviction(false),
@@ -61,6 +66,10 @@ func cmdCache(argv []string) error {
 		msg = fmt.Sprintf("Ok (%d evicted)", cnt)
 	case "path":
 		msg = c.Path()
+	case "refresh-metadata":
+		var cnt int
+		cnt, err = ghasum.RefreshMetadata(&c)
+		msg = fmt.Sprintf("Ok (%d refreshed)", cnt)
 	default:
 		return fmt.Errorf(`unknown command %q (see "ghasum help cache")`, command)
 	}
@@ -78,18 +87,23 @@ func helpCache() string {
 
 Utilities for managing the ghasum cache. This cache is where ghasum stores and
 looks up repositories it needs to do its job. The maximum age of entries in the
-cache is 5 days, after which it will be evicted.
+cache is 5 days, after which it will be evicted. The cache also stores metadata
+of repositories (see "ghasum help list") and the commits of repositories in the
+cache, which are not evicted.
 
 The available commands are:
 
     clear   Remove all data from the cache.
     evict   Remove old data from the cache.
     path    Show the path to the cache.
+    refresh-metadata
+            Look up the metadata of all repositories in the cache again.
 
 The available flags are:
 
     -cache dir
-        The location of the cache directory. Defaults to a directory named
-        .ghasum/ in the user's home directory.
+        The location of the cache directory. Defaults to
+        $XDG_CACHE_HOME/ghasum/, or a directory named .ghasum/ in the user's
+        home directory if it is unset.
 `
 }
@@ -16,9 +16,312 @@ package main
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chains-project/ghasum/internal/ghasum"
+	"github.com/chains-project/ghasum/internal/gitfs"
+	"github.com/chains-project/ghasum/internal/github"
+)
+
+type (
+	// duration is the value of a flag for a duration that may also be given
+	// as a number of days (e.g. 7d).
+	duration time.Duration
+
+	// replacements is the value of the repeatable -replace flag.
+	replacements map[string]string
+
+	// rewrites is the value of the repeatable -rewrite flag.
+	rewrites github.Rewrites
+
+	// terminal writes to a file while displaying a status line below what is
+	// written, e.g. to show progress in a terminal.
+	terminal struct {
+		file   *os.File
+		status string
+		mu     sync.Mutex
+	}
 )
 
+// clearLine is the ANSI escape sequence that clears the current line of a
+// terminal after returning to its start.
+const clearLine = "\r\x1b[K"
+
+var (
+	// logLevel is the minimum level of messages that are logged.
+	logLevel = new(slog.LevelVar)
+
+	// stderr is where messages are logged and progress is displayed.
+	stderr = &terminal{file: os.Stderr}
+)
+
+func (d *duration) Set(value string) error {
+	parsed, err := parseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	*d = duration(parsed)
+	return nil
+}
+
+func (d *duration) String() string {
+	return ""
+}
+
+func (t *terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.status != "" {
+		_, _ = t.file.WriteString(clearLine)
+		defer func() { _, _ = t.file.WriteString(t.status) }()
+	}
+
+	n, err := t.file.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("could not write to %s: %v", t.file.Name(), err)
+	}
+
+	return n, nil
+}
+
+// progress displays the progress of fetching actions, or clears it once all
+// actions have been fetched.
+func (t *terminal) progress(done, total int) {
+	status := ""
+	if done < total {
+		status = fmt.Sprintf("Fetching %d/%d", done+1, total)
+	}
+
+	t.setStatus(status)
+}
+
+func (t *terminal) setStatus(status string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if status == t.status {
+		return
+	}
+
+	_, _ = t.file.WriteString(clearLine + status)
+	t.status = status
+}
+
+func (r replacements) Set(value string) error {
+	id, dir, _ := strings.Cut(value, "=")
+	if !strings.Contains(id, "/") || dir == "" {
+		return errors.New("must be of the form owner/project[@ref]=dir")
+	}
+
+	if _, ok := r[id]; ok {
+		return fmt.Errorf("%q is replaced more than once", id)
+	}
+
+	r[id] = filepath.Clean(dir)
+	return nil
+}
+
+func (r replacements) String() string {
+	return ""
+}
+
+func (r rewrites) Set(value string) error {
+	from, to, _ := strings.Cut(value, "=")
+	if from == "" || to == "" {
+		return errors.New("must be of the form url=url")
+	}
+
+	if _, ok := r[from]; ok {
+		return fmt.Errorf("%q is rewritten more than once", from)
+	}
+
+	r[from] = to
+	return nil
+}
+
+func (r rewrites) String() string {
+	return ""
+}
+
+func setFetcher(cfg *ghasum.Config, name, mirror string, checksums bool) error {
+	switch {
+	case name == fetcherTarball && checksums:
+		return fmt.Errorf("-%s %s cannot be used to compute checksums", flagNameFetcher, fetcherTarball)
+	case name == fetcherMirror && mirror == "":
+		return fmt.Errorf("-%s %s requires -%s", flagNameFetcher, fetcherMirror, flagNameMirror)
+	case name != fetcherMirror && mirror != "":
+		return fmt.Errorf("-%s can only be used with -%s %s", flagNameMirror, flagNameFetcher, fetcherMirror)
+	}
+
+	switch name {
+	case "", fetcherGoGit:
+		cfg.Fetcher = &github.GoGit{}
+	case fetcherGit:
+		cfg.Fetcher = &github.Git{}
+	case fetcherTarball:
+		cfg.Fetcher = &github.Tarball{}
+	case fetcherMirror:
+		cfg.Fetcher = &github.Mirror{Dir: mirror}
+	default:
+		return fmt.Errorf("unknown fetcher %q", name)
+	}
+
+	return nil
+}
+
+// getCommitSigners reads the allowed signers files in the given directory, each
+// named after the owner it applies to (e.g. dir/owner or dir/host/owner).
+func getCommitSigners(dir string) (map[string][]byte, error) {
+	signers := make(map[string][]byte, 0)
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case file != dir && strings.HasPrefix(entry.Name(), "."):
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		case !entry.Type().IsRegular():
+			return nil
+		}
+
+		owner, err := filepath.Rel(dir, file)
+		if err != nil {
+			return fmt.Errorf("could not determine the owner of %q: %v", file, err)
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("could not read allowed signers of %q: %v", owner, err)
+		}
+
+		signers[filepath.ToSlash(owner)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Join(errUnexpected, err)
+	}
+
+	return signers, nil
+}
+
+// getProgress returns the function to report progress with, which is nil if
+// progress should not be displayed because standard error is not a terminal.
+func getProgress() func(done, total int) {
+	info, err := stderr.file.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+
+	return stderr.progress
+}
+
+func getGitHub(serverUrl string) (host, api string, err error) {
+	if serverUrl == "" {
+		serverUrl = os.Getenv(github.ServerUrlEnv)
+		api = os.Getenv("GITHUB_API_URL")
+	}
+
+	if serverUrl == "" {
+		return "", "", nil
+	}
+
+	u, err := url.Parse(serverUrl)
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("invalid GitHub URL %q", serverUrl)
+	}
+
+	return strings.ToLower(u.Host), api, nil
+}
+
+func getRepo(target, rev string) (fs.FS, error) {
+	if rev != "" {
+		return getRevision(target, rev)
+	}
+
+	repo, err := os.OpenRoot(target)
+	if err != nil {
+		return nil, errors.Join(errUnexpected, err)
+	}
+
+	return repo.FS(), nil
+}
+
+// getRevision returns the file system of the git repository at target as of
+// the given revision.
+func getRevision(target, rev string) (fs.FS, error) {
+	repo, err := gitfs.Open(target, rev)
+	switch {
+	case errors.Is(err, gitfs.ErrRevisionNotFound):
+		return nil, fmt.Errorf("revision %q not found", rev)
+	case err != nil:
+		return nil, errors.Join(errUnexpected, err)
+	}
+
+	return repo, nil
+}
+
+// parseDuration parses a non-negative duration, either a number of days with
+// the suffix "d" or in the format of [time.ParseDuration].
+func parseDuration(value string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(value, "d"); ok {
+		days, err := strconv.ParseUint(n, 10, 16)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number of days %q", value)
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	return d, nil
+}
+
+func getSumfile(sumfile string, perWorkflow bool) (string, error) {
+	if sumfile == "" {
+		return "", nil
+	}
+
+	if perWorkflow {
+		return "", fmt.Errorf("-%s cannot be used with -%s", flagNameSumfile, flagNameSumfilePerWorkflow)
+	}
+
+	sumfile = filepath.ToSlash(filepath.Clean(sumfile))
+	if !fs.ValidPath(sumfile) {
+		return "", fmt.Errorf("invalid sumfile path %q, must be relative to and inside the target", sumfile)
+	}
+
+	return sumfile, nil
+}
+
+// setVerbosity sets what is logged, either informational messages if verbose
+// is set or also debug messages if debug is set.
+func setVerbosity(verbose, debug bool) {
+	switch {
+	case debug:
+		logLevel.Set(slog.LevelDebug)
+	case verbose:
+		logLevel.Set(slog.LevelInfo)
+	}
+}
+
 func getTarget(args []string) (string, error) {
 	if len(args) == 0 {
 		wd, err := os.Getwd()
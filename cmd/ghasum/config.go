@@ -0,0 +1,444 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/chains-project/ghasum/internal/cache"
+	"github.com/chains-project/ghasum/internal/github"
+)
+
+type (
+	// config is the content of a ghasum configuration file.
+	config struct {
+		// Cache is the default for the -cache flag.
+		Cache *string `yaml:"cache"`
+
+		// GitHubURL is the default for the -github-url flag.
+		GitHubURL *string `yaml:"github-url"`
+
+		// NoEvict is the default for the -no-evict flag.
+		NoEvict *bool `yaml:"no-evict"`
+
+		// NoTransitive is the default for the -no-transitive flag.
+		NoTransitive *bool `yaml:"no-transitive"`
+
+		// Offline is the default for the -offline flag.
+		Offline *bool `yaml:"offline"`
+
+		// MinAge overrides the -min-age flag for specific actions, identified as
+		// owner/project[@ref].
+		MinAge map[string]string `yaml:"min-age"`
+
+		// Replace adds to the -replace flag, mapping actions identified as
+		// owner/project[@ref] to a directory relative to the configuration
+		// file.
+		Replace map[string]string `yaml:"replace"`
+	}
+
+	// settings is the effective configuration, combined from all sources.
+	settings struct {
+		// values are the configured defaults of flags, by flag name.
+		values map[string]setting
+
+		// minAges are the minimum age overrides, by action.
+		minAges map[string]setting
+
+		// replace are the replaced actions, by action.
+		replace map[string]setting
+	}
+
+	// setting is a configured value together with where it was configured.
+	setting struct {
+		value  string
+		source string
+	}
+)
+
+const (
+	// configFile is the path of the ghasum configuration file of a repository,
+	// relative to the target.
+	configFile = ".github/ghasum.yml"
+
+	// envPrefix is the prefix of environment variables that configure flags.
+	envPrefix = "GHASUM_"
+
+	// userConfig is the path of the ghasum configuration file of a user,
+	// relative to the user's configuration directory.
+	userConfig = "ghasum/config"
+
+	sourceDefault = "default"
+)
+
+// configurable are the flags that can be configured, and whether they are
+// boolean flags.
+var configurable = map[string]bool{
+	flagNameCache:        false,
+	flagNameGitHubURL:    false,
+	flagNameNoEvict:      true,
+	flagNameNoTransitive: true,
+	flagNameOffline:      true,
+}
+
+// userOnly are the configurable flags that the configuration file of a
+// repository cannot configure, because they affect the user's machine or weaken
+// the verification that protects against the repository.
+var userOnly = []string{
+	flagNameCache,
+	flagNameGitHubURL,
+	flagNameNoEvict,
+	flagNameNoTransitive,
+	flagNameOffline,
+	flagNameReplace,
+}
+
+func cmdConfig(argv []string) error {
+	flags := flag.NewFlagSet(cmdNameConfig, flag.ContinueOnError)
+
+	flags.Usage = func() { fmt.Fprintln(os.Stderr) }
+	if err := flags.Parse(argv); err != nil {
+		return errUsage
+	}
+
+	args := flags.Args()
+	if len(args) > 1 {
+		return errUsage
+	}
+
+	target, err := getTarget(args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := os.OpenRoot(target)
+	if err != nil {
+		return errors.Join(errUnexpected, err)
+	}
+
+	settings, err := getSettings(repo.FS())
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(settings)
+	return nil
+}
+
+// configure loads the configuration for the target repository, which may be
+// nil, and uses it for the flags that were not set explicitly.
+func configure(flags *flag.FlagSet, repo fs.FS) (*settings, error) {
+	s, err := getSettings(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := make(map[string]struct{}, 0)
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = struct{}{}
+	})
+
+	for name, setting := range s.values {
+		if _, ok := explicit[name]; ok || flags.Lookup(name) == nil {
+			continue
+		}
+
+		if err := flags.Set(name, setting.value); err != nil {
+			return nil, fmt.Errorf("invalid %s in %s: %v", name, setting.source, err)
+		}
+	}
+
+	return s, nil
+}
+
+// getSettings combines the configuration file of the target repository, which
+// may be nil, the user's configuration file, and the environment, in order of
+// increasing precedence.
+func getSettings(repo fs.FS) (*settings, error) {
+	s := settings{
+		values:  make(map[string]setting, len(configurable)),
+		minAges: make(map[string]setting, 0),
+		replace: make(map[string]setting, 0),
+	}
+
+	if repo != nil {
+		cfg, err := readConfig(repo, configFile, configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		names := slices.Collect(maps.Keys(cfg.values()))
+		if len(cfg.Replace) > 0 {
+			names = append(names, flagNameReplace)
+		}
+
+		for _, name := range names {
+			if slices.Contains(userOnly, name) {
+				return nil, fmt.Errorf("%s cannot be configured in %s, only in the user's configuration file or the environment", name, configFile)
+			}
+		}
+
+		s.add(&cfg, configFile)
+	}
+
+	if file, err := userConfigFile(); err == nil {
+		cfg, err := readConfig(os.DirFS(filepath.Dir(file)), filepath.Base(file), file)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, dir := range cfg.Replace {
+			if !filepath.IsAbs(dir) {
+				cfg.Replace[id] = filepath.Join(filepath.Dir(file), dir)
+			}
+		}
+
+		s.add(&cfg, file)
+	}
+
+	for name, isBool := range configurable {
+		env := envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+
+		if _, err := strconv.ParseBool(value); isBool && err != nil {
+			return nil, fmt.Errorf("invalid value %q for $%s, must be a boolean", value, env)
+		}
+
+		s.values[name] = setting{value: value, source: "$" + env}
+	}
+
+	return &s, nil
+}
+
+// values returns the values of the flags that are configured, by flag name.
+func (cfg *config) values() map[string]string {
+	values := make(map[string]string, len(configurable))
+	if cfg.Cache != nil {
+		values[flagNameCache] = *cfg.Cache
+	}
+
+	if cfg.GitHubURL != nil {
+		values[flagNameGitHubURL] = *cfg.GitHubURL
+	}
+
+	if cfg.NoEvict != nil {
+		values[flagNameNoEvict] = strconv.FormatBool(*cfg.NoEvict)
+	}
+
+	if cfg.NoTransitive != nil {
+		values[flagNameNoTransitive] = strconv.FormatBool(*cfg.NoTransitive)
+	}
+
+	if cfg.Offline != nil {
+		values[flagNameOffline] = strconv.FormatBool(*cfg.Offline)
+	}
+
+	return values
+}
+
+// add adds the given configuration, from the given source, to the settings.
+func (s *settings) add(cfg *config, source string) {
+	for name, value := range cfg.values() {
+		s.values[name] = setting{value: value, source: source}
+	}
+
+	for id, value := range cfg.MinAge {
+		s.minAges[id] = setting{value: value, source: source}
+	}
+
+	for id, dir := range cfg.Replace {
+		s.replace[id] = setting{value: dir, source: source}
+	}
+}
+
+// getMinAges returns the configured minimum age overrides. Overrides from the
+// configuration file of the target may not be lower than the given minimum age
+// of the -min-age flag, so that the repository cannot weaken the check.
+func (s *settings) getMinAges(minAge time.Duration) (map[string]time.Duration, error) {
+	minAges := make(map[string]time.Duration, len(s.minAges))
+	for id, setting := range s.minAges {
+		if !strings.Contains(id, "/") {
+			return nil, fmt.Errorf("invalid min-age in %s: %q must be of the form owner/project[@ref]", setting.source, id)
+		}
+
+		d, err := parseDuration(setting.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min-age in %s for %q: %v", setting.source, id, err)
+		}
+
+		if setting.source == configFile && d < minAge {
+			return nil, fmt.Errorf("min-age for %q cannot be lower than %s in %s, only in the user's configuration file", id, minAge, setting.source)
+		}
+
+		minAges[id] = d
+	}
+
+	return minAges, nil
+}
+
+// addReplacements adds the configured replacements to the given replacements of
+// the -replace flag, unless the flag replaces the same action.
+func (s *settings) addReplacements(r replacements) error {
+	for id, setting := range s.replace {
+		if _, ok := r[id]; ok {
+			continue
+		}
+
+		if err := r.Set(id + "=" + setting.value); err != nil {
+			return fmt.Errorf("invalid replace in %s: %v", setting.source, err)
+		}
+	}
+
+	return nil
+}
+
+// String returns the effective configuration in the configuration file format,
+// annotated with the source of every value.
+func (s *settings) String() string {
+	var sb strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(configurable)) {
+		current, ok := s.values[name]
+		if !ok {
+			current = setting{value: "false", source: sourceDefault}
+			switch name {
+			case flagNameCache:
+				current.value, _ = cache.DefaultLocation()
+			case flagNameGitHubURL:
+				current.value = "https://" + github.DefaultHost
+				if url := os.Getenv(github.ServerUrlEnv); url != "" {
+					current = setting{value: url, source: "$" + github.ServerUrlEnv}
+				}
+			}
+		}
+
+		fmt.Fprintf(&sb, "%s: %s # %s\n", name, current.value, current.source)
+	}
+
+	writeMapping(&sb, flagNameMinAge, s.minAges)
+	writeMapping(&sb, flagNameReplace, s.replace)
+
+	return sb.String()
+}
+
+// writeMapping writes the given mapping in the configuration file format, if it
+// is not empty, annotated with the source of every entry.
+func writeMapping(sb *strings.Builder, key string, mapping map[string]setting) {
+	if len(mapping) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "%s:\n", key)
+	for _, id := range slices.Sorted(maps.Keys(mapping)) {
+		current := mapping[id]
+		fmt.Fprintf(sb, "  %s: %s # %s\n", id, current.value, current.source)
+	}
+}
+
+// readConfig reads the ghasum configuration file with the given name, which is
+// described by source. It is not an error if the file does not exist.
+func readConfig(fsys fs.FS, name, source string) (config, error) {
+	var cfg config
+
+	raw, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, fmt.Errorf("could not read configuration file %s: %v", source, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("invalid configuration file %s: %v", source, err)
+	}
+
+	return cfg, nil
+}
+
+// userConfigFile returns the path of the user's ghasum configuration file, in
+// $XDG_CONFIG_HOME if it is set or the user's configuration directory otherwise.
+func userConfigFile() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return "", fmt.Errorf("could not get the configuration directory: %v", err)
+		}
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(userConfig)), nil
+}
+
+func helpConfig() string {
+	return `usage: ghasum config [target]
+
+Print the effective configuration for the target, including where every value
+comes from. If no target is provided it will default to the current working
+directory.
+
+Flags that are not given explicitly take their value from the following
+sources, in order of precedence:
+
+    1. Environment variables named after the flag, prefixed with GHASUM_ (e.g.
+       GHASUM_NO_EVICT for -no-evict).
+    2. The user's configuration file, located at $XDG_CONFIG_HOME/ghasum/config
+       or in the user's configuration directory (e.g. ~/.config) if it is unset.
+    3. The target's configuration file, located at .github/ghasum.yml.
+
+Configuration files are in YAML with the following keys:
+
+    cache: dir
+        The default for the -cache flag. Not allowed in the target's
+        configuration file.
+    github-url: url
+        The default for the -github-url flag, which takes precedence over
+        $GITHUB_SERVER_URL. Not allowed in the target's configuration file.
+    no-evict: bool
+        The default for the -no-evict flag. Not allowed in the target's
+        configuration file.
+    no-transitive: bool
+        The default for the -no-transitive flag. Not allowed in the target's
+        configuration file.
+    offline: bool
+        The default for the -offline flag. Not allowed in the target's
+        configuration file.
+    min-age:
+        A mapping of actions, as owner/project[@ref], to their minimum age. This
+        overrides the -min-age flag (see "ghasum help verify"). In the target's
+        configuration file a minimum age cannot be lower than the -min-age flag.
+    replace:
+        A mapping of actions, as owner/project[@ref], to a directory relative to
+        the configuration file. This adds to the -replace flag, which takes
+        precedence for the same action. Not allowed in the target's
+        configuration file.
+`
+}
@@ -59,9 +59,13 @@ for the GitHub Actions used in a project to avoid using Actions that changed.
 
 The available commands are:
 
+    audit     Audit the checksums for a repository.
     cache     Manage the ghasum cache.
+    config    Print the effective configuration.
     init      Initialize ghasum for a repository.
     list      View the list of GitHub Actions dependencies.
+    merge     Merge gha.sum files, for use as a git merge driver.
+    sign      Sign the checksums for a repository.
     update    Update the checksums for a repository.
     verify    Verify the checksums for a repository.
     version   Print the ghasum version.
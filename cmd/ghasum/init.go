@@ -19,25 +19,42 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path"
 
 	"github.com/chains-project/ghasum/internal/cache"
+	"github.com/chains-project/ghasum/internal/gha"
 	"github.com/chains-project/ghasum/internal/ghasum"
+	"github.com/chains-project/ghasum/internal/github"
 )
 
 func cmdInit(argv []string) error {
 	var (
-		flags            = flag.NewFlagSet(cmdNameInit, flag.ContinueOnError)
-		flagCache        = flags.String(flagNameCache, "", "")
-		flagNoCache      = flags.Bool(flagNameNoCache, false, "")
-		flagNoEvict      = flags.Bool(flagNameNoEvict, false, "")
-		flagNoTransitive = flags.Bool(flagNameNoTransitive, false, "")
+		flags                  = flag.NewFlagSet(cmdNameInit, flag.ContinueOnError)
+		flagCache              = flags.String(flagNameCache, "", "")
+		flagDebug              = flags.Bool(flagNameDebug, false, "")
+		flagFetcher            = flags.String(flagNameFetcher, "", "")
+		flagGitHubConnect      = flags.Bool(flagNameGitHubConnect, false, "")
+		flagGitHubURL          = flags.String(flagNameGitHubURL, "", "")
+		flagMirror             = flags.String(flagNameMirror, "", "")
+		flagNoCache            = flags.Bool(flagNameNoCache, false, "")
+		flagNoEvict            = flags.Bool(flagNameNoEvict, false, "")
+		flagNoTransitive       = flags.Bool(flagNameNoTransitive, false, "")
+		flagReplace            = make(replacements)
+		flagRewrite            = make(rewrites)
+		flagSumfile            = flags.String(flagNameSumfile, "", "")
+		flagSumfilePerWorkflow = flags.Bool(flagNameSumfilePerWorkflow, false, "")
+		flagVerbose            = flags.Bool(flagNameVerbose, false, "")
 	)
 
+	flags.Var(flagReplace, flagNameReplace, "")
+	flags.Var(flagRewrite, flagNameRewrite, "")
 	flags.Usage = func() { fmt.Fprintln(os.Stderr) }
 	if err := flags.Parse(argv); err != nil {
 		return errUsage
 	}
 
+	setVerbosity(*flagVerbose, *flagDebug)
+
 	args := flags.Args()
 	if len(args) > 1 {
 		return errUsage
@@ -48,6 +65,30 @@ func cmdInit(argv []string) error {
 		return err
 	}
 
+	sumfile, err := getSumfile(*flagSumfile, *flagSumfilePerWorkflow)
+	if err != nil {
+		return err
+	}
+
+	repo, err := os.OpenRoot(target)
+	if err != nil {
+		return errors.Join(errUnexpected, err)
+	}
+
+	settings, err := configure(flags, repo.FS())
+	if err != nil {
+		return err
+	}
+
+	if err = settings.addReplacements(flagReplace); err != nil {
+		return err
+	}
+
+	host, api, err := getGitHub(*flagGitHubURL)
+	if err != nil {
+		return err
+	}
+
 	c, err := cache.New(
 		cache.WithLocation(*flagCache),
 		cache.WithEviction(!*flagNoEvict),
@@ -57,27 +98,46 @@ func cmdInit(argv []string) error {
 		return errors.Join(errCache, err)
 	}
 
-	repo, err := os.OpenRoot(target)
-	if err != nil {
-		return errors.Join(errUnexpected, err)
+	cfg := ghasum.Config{
+		Repo:               repo.FS(),
+		Path:               target,
+		Sumfile:            sumfile,
+		SumfilePerWorkflow: *flagSumfilePerWorkflow,
+		Cache:              c,
+		Transitive:         !(*flagNoTransitive),
+		Host:               host,
+		API:                api,
+		Fallback:           *flagGitHubConnect,
+		Rewrites:           github.Rewrites(flagRewrite),
+		Replace:            flagReplace,
+		Progress:           getProgress(),
 	}
 
-	cfg := ghasum.Config{
-		Repo:       repo.FS(),
-		Path:       target,
-		Cache:      c,
-		Transitive: !(*flagNoTransitive),
+	if err = setFetcher(&cfg, *flagFetcher, *flagMirror, true); err != nil {
+		return err
 	}
 
-	if err := ghasum.Initialize(&cfg); err != nil {
+	err = ghasum.Initialize(&cfg)
+	if errors.Is(err, ghasum.ErrReplaced) {
+		return errors.Join(errFailure, err)
+	} else if err != nil {
 		return errors.Join(errUnexpected, err)
 	}
 
-	fmt.Println(`Ok
+	tracked := ghasum.DefaultSumfile(cfg.Repo)
+	switch {
+	case *flagSumfilePerWorkflow:
+		tracked = "the " + path.Join(gha.WorkflowsDir(cfg.Repo), "*.sum") + " files"
+	case sumfile != "":
+		tracked = sumfile
+	}
+
+	fmt.Printf(`Ok
 
 Next:
-1. Track .github/workflows/gha.sum with git.
-2. Integrate ghasum into the project's workflows.`)
+1. Track %s with git.
+2. Integrate ghasum into the project's workflows.
+`, tracked)
 	return nil
 }
 
@@ -93,12 +153,54 @@ The available flags are:
     -cache dir
         The location of the cache directory. This is where ghasum stores and
         looks up repositories it needs.
-        Defaults to a directory named .ghasum in the user's home directory.
+        Defaults to $XDG_CACHE_HOME/ghasum, or a directory named .ghasum in the
+        user's home directory if it is unset.
+    -debug
+        Log debug messages to stderr in addition to those of -v, such as every
+        attempt to fetch a repository.
+    -fetcher name
+        The method used to fetch repositories, one of "go-git", "git" (using
+        the system git binary and its configuration), or "mirror" (see
+        -mirror).
+        Defaults to "go-git".
+    -github-connect
+        Fetch actions used without an explicit host from github.com if their
+        repository does not exist on the GitHub instance given by -github-url,
+        like a GitHub Enterprise Server does with GitHub Connect.
+    -github-url url
+        The URL of the GitHub instance, e.g. a GitHub Enterprise Server, that
+        houses actions used without an explicit host.
+        Defaults to $GITHUB_SERVER_URL, or https://github.com if it is unset.
+    -mirror dir
+        The directory to read repositories from with "-fetcher mirror". The
+        repository owner/project is read from the (bare) git repository at
+        owner/project.git or owner/project in the directory.
     -no-cache
         Disable the use of the cache. Makes the -cache flag ineffective.
     -no-evict
         Disable cache eviction.
     -no-transitive
         Do not compute checksums for transitive actions.
+    -replace owner/project[@ref]=dir
+        Use the local directory dir instead of the repository of the action
+        owner/project at ref, or at any ref if @ref is omitted. Can be used
+        multiple times. Checksums are never stored for replaced actions, so
+        initialization fails if any action used is replaced. Can also be
+        configured (see "ghasum help config").
+    -rewrite from=to
+        Use the URL prefix to instead of from for repositories and the API, in
+        the style of git's url.<base>.insteadOf (e.g. to use an internal
+        mirror). The longest matching prefix is used. Can be used multiple
+        times.
+    -sumfile path
+        The path to the gha.sum file, relative to the target.
+        Defaults to .github/workflows/gha.sum.
+    -sumfile-per-workflow
+        Use a separate checksum file for every workflow, located next to the
+        workflow it belongs to (e.g. ci.yml.sum for ci.yml). Cannot be used
+        together with -sumfile.
+    -v
+        Log what is being done to stderr, such as which actions are fetched and
+        checksummed and how long that took.
 `
 }
@@ -22,23 +22,40 @@ import (
 
 	"github.com/chains-project/ghasum/internal/cache"
 	"github.com/chains-project/ghasum/internal/ghasum"
+	"github.com/chains-project/ghasum/internal/github"
 )
 
 func cmdList(argv []string) error {
 	var (
-		flags            = flag.NewFlagSet(cmdNameUpdate, flag.ContinueOnError)
-		flagCache        = flags.String(flagNameCache, "", "")
-		flagNoCache      = flags.Bool(flagNameNoCache, false, "")
-		flagNoEvict      = flags.Bool(flagNameNoEvict, false, "")
-		flagNoTransitive = flags.Bool(flagNameNoTransitive, false, "")
-		flagOffline      = flags.Bool(flagNameOffline, false, "")
+		flags             = flag.NewFlagSet(cmdNameUpdate, flag.ContinueOnError)
+		flagCache         = flags.String(flagNameCache, "", "")
+		flagDebug         = flags.Bool(flagNameDebug, false, "")
+		flagFromSumfile   = flags.Bool(flagNameFromSumfile, false, "")
+		flagFetcher       = flags.String(flagNameFetcher, "", "")
+		flagGitHubConnect = flags.Bool(flagNameGitHubConnect, false, "")
+		flagGitHubURL     = flags.String(flagNameGitHubURL, "", "")
+		flagMetadataTTL   = flags.Duration(flagNameMetadataTTL, 0, "")
+		flagMirror        = flags.String(flagNameMirror, "", "")
+		flagNoCache       = flags.Bool(flagNameNoCache, false, "")
+		flagNoEvict       = flags.Bool(flagNameNoEvict, false, "")
+		flagNoTransitive  = flags.Bool(flagNameNoTransitive, false, "")
+		flagOffline       = flags.Bool(flagNameOffline, false, "")
+		flagReplace       = make(replacements)
+		flagRev           = flags.String(flagNameRev, "", "")
+		flagRewrite       = make(rewrites)
+		flagSumfile       = flags.String(flagNameSumfile, "", "")
+		flagVerbose       = flags.Bool(flagNameVerbose, false, "")
 	)
 
+	flags.Var(flagReplace, flagNameReplace, "")
+	flags.Var(flagRewrite, flagNameRewrite, "")
 	flags.Usage = func() { fmt.Fprintln(os.Stderr) }
 	if err := flags.Parse(argv); err != nil {
 		return errUsage
 	}
 
+	setVerbosity(*flagVerbose, *flagDebug)
+
 	args := flags.Args()
 	if len(args) > 1 {
 		return errUsage
@@ -49,34 +66,69 @@ func cmdList(argv []string) error {
 		return err
 	}
 
-	c, err := cache.New(
-		cache.WithLocation(*flagCache),
-		cache.WithEviction(!*flagNoEvict),
-		cache.WithEphemeralCache(*flagNoCache),
-	)
+	sumfile, err := getSumfile(*flagSumfile, false)
 	if err != nil {
-		return errors.Join(errCache, err)
+		return err
 	}
 
-	repo, err := os.OpenRoot(target)
+	repo, err := getRepo(target, *flagRev)
 	if err != nil {
-		return errors.Join(errUnexpected, err)
+		return err
+	}
+
+	settings, err := configure(flags, repo)
+	if err != nil {
+		return err
+	}
+
+	if err = settings.addReplacements(flagReplace); err != nil {
+		return err
+	}
+
+	host, api, err := getGitHub(*flagGitHubURL)
+	if err != nil {
+		return err
 	}
 
 	cfg := ghasum.Config{
-		Repo:       repo.FS(),
-		Path:       target,
-		Cache:      c,
-		Offline:    *flagOffline,
-		Transitive: !(*flagNoTransitive),
+		Repo:        repo,
+		Path:        target,
+		Sumfile:     sumfile,
+		Offline:     *flagOffline,
+		Transitive:  !(*flagNoTransitive),
+		Host:        host,
+		API:         api,
+		Fallback:    *flagGitHubConnect,
+		MetadataTTL: *flagMetadataTTL,
+		Rewrites:    github.Rewrites(flagRewrite),
+		Replace:     flagReplace,
+		Progress:    getProgress(),
+	}
+
+	if err = setFetcher(&cfg, *flagFetcher, *flagMirror, false); err != nil {
+		return err
+	}
+
+	list := ghasum.ListRecorded
+	if !*flagFromSumfile {
+		cfg.Cache, err = cache.New(
+			cache.WithLocation(*flagCache),
+			cache.WithEviction(!*flagNoEvict),
+			cache.WithEphemeralCache(*flagNoCache || *flagFetcher == fetcherTarball),
+		)
+		if err != nil {
+			return errors.Join(errCache, err)
+		}
+
+		list = ghasum.List
 	}
 
-	list, err := ghasum.List(&cfg)
+	out, err := list(&cfg)
 	if err != nil {
 		return errors.Join(errUnexpected, err)
 	}
 
-	fmt.Print(list)
+	fmt.Print(out)
 	return nil
 }
 
@@ -91,7 +143,39 @@ The available flags are:
     -cache dir
         The location of the cache directory. This is where ghasum stores and
         looks up repositories it needs.
-        Defaults to a directory named .ghasum in the user's home directory.
+        Defaults to $XDG_CACHE_HOME/ghasum, or a directory named .ghasum in the
+        user's home directory if it is unset.
+    -debug
+        Log debug messages to stderr in addition to those of -v, such as every
+        attempt to fetch a repository.
+    -fetcher name
+        The method used to fetch repositories, one of "go-git", "git" (using
+        the system git binary and its configuration), "tarball" (downloading
+        tarballs from GitHub, without using the cache), or "mirror" (see
+        -mirror).
+        Defaults to "go-git".
+    -from-sumfile
+        List the dependencies as recorded in the gha.sum file instead of
+        discovering them. Does not use the cache or the internet. Requires a
+        gha.sum file with a dependency graph, which is recorded by ghasum from
+        sumfile version 2 onwards.
+    -github-connect
+        Fetch actions used without an explicit host from github.com if their
+        repository does not exist on the GitHub instance given by -github-url,
+        like a GitHub Enterprise Server does with GitHub Connect.
+    -github-url url
+        The URL of the GitHub instance, e.g. a GitHub Enterprise Server, that
+        houses actions used without an explicit host.
+        Defaults to $GITHUB_SERVER_URL, or https://github.com if it is unset.
+    -metadata-ttl duration
+        How long the metadata of repositories, such as whether they are
+        archived, is kept in the cache before it is looked up again (e.g. 1h).
+        With -offline the metadata in the cache is used regardless of its age.
+        Defaults to 24h.
+    -mirror dir
+        The directory to read repositories from with "-fetcher mirror". The
+        repository owner/project is read from the (bare) git repository at
+        owner/project.git or owner/project in the directory.
     -no-cache
         Disable the use of the cache. Makes the -cache flag ineffective.
     -no-evict
@@ -101,5 +185,24 @@ The available flags are:
     -offline
         Run without fetching repositories or metadata from the internet. If the
         cache is missing an entry it causes an error.
+    -replace owner/project[@ref]=dir
+        Use the local directory dir instead of the repository of the action
+        owner/project at ref, or at any ref if @ref is omitted. Can be used
+        multiple times. Replaced actions are marked in the output. Can also
+        be configured (see "ghasum help config").
+    -rev rev
+        Use the target at the given git revision (e.g. a branch, tag, or commit
+        SHA) instead of its working tree. The target must be a git repository.
+    -rewrite from=to
+        Use the URL prefix to instead of from for repositories and the API, in
+        the style of git's url.<base>.insteadOf (e.g. to use an internal
+        mirror). The longest matching prefix is used. Can be used multiple
+        times.
+    -sumfile path
+        The path to the gha.sum file, relative to the target. Only applies
+        together with -from-sumfile. Defaults to .github/workflows/gha.sum.
+    -v
+        Log what is being done to stderr, such as which actions are fetched and
+        checksummed and how long that took.
 `
 }
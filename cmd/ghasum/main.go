@@ -17,6 +17,7 @@ package main
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 )
 
@@ -29,10 +30,14 @@ type (
 )
 
 const (
+	cmdNameAudit   = "audit"
 	cmdNameCache   = "cache"
+	cmdNameConfig  = "config"
 	cmdNameHelp    = "help"
 	cmdNameInit    = "init"
 	cmdNameList    = "list"
+	cmdNameMerge   = "merge"
+	cmdNameSign    = "sign"
 	cmdNameUpdate  = "update"
 	cmdNameVerify  = "verify"
 	cmdNameVersion = "version"
@@ -46,12 +51,39 @@ const (
 )
 
 const (
-	flagNameCache        = "cache"
-	flagNameForce        = "force"
-	flagNameNoCache      = "no-cache"
-	flagNameNoEvict      = "no-evict"
-	flagNameNoTransitive = "no-transitive"
-	flagNameOffline      = "offline"
+	fetcherGit     = "git"
+	fetcherGoGit   = "go-git"
+	fetcherMirror  = "mirror"
+	fetcherTarball = "tarball"
+)
+
+const (
+	flagNameCache              = "cache"
+	flagNameDB                 = "db"
+	flagNameDebug              = "debug"
+	flagNameFetcher            = "fetcher"
+	flagNameForce              = "force"
+	flagNameFromSumfile        = "from-sumfile"
+	flagNameGitHubConnect      = "github-connect"
+	flagNameGitHubURL          = "github-url"
+	flagNameInstall            = "install"
+	flagNameKey                = "key"
+	flagNameMetadataTTL        = "metadata-ttl"
+	flagNameMinAge             = "min-age"
+	flagNameMirror             = "mirror"
+	flagNameNoCache            = "no-cache"
+	flagNameNoEvict            = "no-evict"
+	flagNameNoTransitive       = "no-transitive"
+	flagNameOffline            = "offline"
+	flagNameReplace            = "replace"
+	flagNameRequireSigned      = "require-signed"
+	flagNameRev                = "rev"
+	flagNameRewrite            = "rewrite"
+	flagNameSigners            = "signers"
+	flagNameSumfile            = "sumfile"
+	flagNameSumfilePerWorkflow = "sumfile-per-workflow"
+	flagNameSumfileRev         = "sumfile-rev"
+	flagNameVerbose            = "v"
 )
 
 var (
@@ -62,20 +94,28 @@ var (
 )
 
 var commands = map[string]Command{
+	cmdNameAudit:   cmdAudit,
 	cmdNameCache:   cmdCache,
+	cmdNameConfig:  cmdConfig,
 	cmdNameHelp:    cmdHelp,
 	cmdNameInit:    cmdInit,
 	cmdNameList:    cmdList,
+	cmdNameMerge:   cmdMerge,
+	cmdNameSign:    cmdSign,
 	cmdNameUpdate:  cmdUpdate,
 	cmdNameVerify:  cmdVerify,
 	cmdNameVersion: cmdVersion,
 }
 
 var helpers = map[string]Helper{
+	cmdNameAudit:   helpAudit,
 	cmdNameCache:   helpCache,
+	cmdNameConfig:  helpConfig,
 	cmdNameHelp:    help,
 	cmdNameInit:    helpInit,
 	cmdNameList:    helpList,
+	cmdNameMerge:   helpMerge,
+	cmdNameSign:    helpSign,
 	cmdNameUpdate:  helpUpdate,
 	cmdNameVerify:  helpVerify,
 	cmdNameVersion: helpVersion,
@@ -110,7 +150,15 @@ func run() int {
 		return exitCodeUsage
 	}
 
+	logLevel.Set(slog.LevelWarn)
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{
+		Level:       logLevel,
+		ReplaceAttr: withoutTime,
+	})))
+
 	err := fn(os.Args[2:])
+	stderr.setStatus("")
+
 	switch {
 	case err == nil:
 		return exitCodeSuccess
@@ -126,3 +174,12 @@ func run() int {
 		return exitCodeError
 	}
 }
+
+// withoutTime removes the time from log messages.
+func withoutTime(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Key == slog.TimeKey && len(groups) == 0 {
+		return slog.Attr{}
+	}
+
+	return attr
+}
@@ -15,6 +15,10 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -23,12 +27,34 @@ import (
 
 func TestMain(m *testing.M) {
 	commands := map[string]func(){
-		"ghasum": main,
+		"ghasum":          main,
+		"ghasum-stub-api": stubApi,
 	}
 
 	testscript.Main(m, commands)
 }
 
+// stubApi runs ghasum against a stub of the GitHub REST API, in which every
+// commit comparison reports that the commits have diverged and every other
+// endpoint responds with 404 Not Found.
+func stubApi() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/compare/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status":"diverged"}`))
+	}))
+
+	if len(os.Args) > 1 {
+		rewrite := "https://api.github.com=" + server.URL
+		os.Args = slices.Concat(os.Args[:2], []string{"-rewrite", rewrite}, os.Args[2:])
+	}
+
+	main()
+}
+
 func TestCli(t *testing.T) {
 	t.Parallel()
 
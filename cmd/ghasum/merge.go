@@ -0,0 +1,154 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chains-project/ghasum/internal/ghasum"
+)
+
+func cmdMerge(argv []string) error {
+	var (
+		flags                  = flag.NewFlagSet(cmdNameMerge, flag.ContinueOnError)
+		flagInstall            = flags.Bool(flagNameInstall, false, "")
+		flagSumfile            = flags.String(flagNameSumfile, "", "")
+		flagSumfilePerWorkflow = flags.Bool(flagNameSumfilePerWorkflow, false, "")
+	)
+
+	flags.Usage = func() { fmt.Fprintln(os.Stderr) }
+	if err := flags.Parse(argv); err != nil {
+		return errUsage
+	}
+
+	args := flags.Args()
+	if *flagInstall {
+		if len(args) > 1 {
+			return errUsage
+		}
+
+		target, err := getTarget(args)
+		if err != nil {
+			return err
+		}
+
+		sumfile, err := getSumfile(*flagSumfile, *flagSumfilePerWorkflow)
+		if err != nil {
+			return err
+		}
+
+		repo, err := getRepo(target, "")
+		if err != nil {
+			return err
+		}
+
+		cfg := ghasum.Config{
+			Repo:               repo,
+			Path:               target,
+			Sumfile:            sumfile,
+			SumfilePerWorkflow: *flagSumfilePerWorkflow,
+		}
+
+		if err := ghasum.InstallMergeDriver(&cfg); err != nil {
+			return errors.Join(errUnexpected, err)
+		}
+
+		fmt.Println(`Ok
+
+Next:
+1. Track .gitattributes with git.
+2. Install the merge driver in every clone of the repository.`)
+		return nil
+	}
+
+	if len(args) != 3 {
+		return errUsage
+	}
+
+	files := make([][]byte, len(args))
+	for i, file := range args {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return errors.Join(errUnexpected, err)
+		}
+
+		files[i] = content
+	}
+
+	merged, report, err := ghasum.Merge(files[0], files[1], files[2])
+	if err != nil {
+		return errors.Join(errUnexpected, err)
+	}
+
+	if err := os.WriteFile(args[1], []byte(merged), 0o644); err != nil {
+		return errors.Join(errUnexpected, err)
+	}
+
+	return reportMerge(&report)
+}
+
+func reportMerge(report *ghasum.MergeReport) error {
+	if cnt := len(report.Conflicts); cnt > 0 {
+		var sb strings.Builder
+
+		sb.WriteString(fmt.Sprintf("%d conflict(s) occurred during merging:\n", cnt))
+		for _, conflict := range report.Conflicts {
+			sb.WriteString("  " + string(conflict) + "\n")
+		}
+
+		return errors.Join(errFailure, errors.New(sb.String()))
+	}
+
+	fmt.Println("Ok")
+	return nil
+}
+
+func helpMerge() string {
+	return `usage: ghasum merge [flags] <base> <ours> <theirs>
+
+Merge two gha.sum files, ours and theirs, that both derive from a common base.
+The result is written to ours. This command is meant to be used as a git merge
+driver, see the -install flag.
+
+Checksums that were added, changed, or removed on only one side are merged
+automatically. If a checksum was changed differently on both sides this command
+errors with a non-zero exit code, and the conflicting checksums are marked in
+ours using conflict markers. If the dependency graphs cannot be merged this
+command also errors, and the graph is omitted from ours.
+
+The merge driver can be installed for a repository using:
+
+    ghasum merge -install [target]
+
+If no target is provided it will default to the current working directory. This
+updates the .gitattributes file and the local git configuration of the target.
+
+The available flags are:
+
+    -install
+        Install ghasum as the git merge driver for the target's gha.sum file.
+    -sumfile path
+        The path to the gha.sum file, relative to the target. Only applies when
+        installing. Defaults to .github/workflows/gha.sum.
+    -sumfile-per-workflow
+        Install the merge driver for the separate checksum file of every
+        workflow. Only applies when installing. Cannot be used together with
+        -sumfile.
+`
+}
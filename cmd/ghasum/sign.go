@@ -0,0 +1,129 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/chains-project/ghasum/internal/gha"
+	"github.com/chains-project/ghasum/internal/ghasum"
+)
+
+func cmdSign(argv []string) error {
+	var (
+		flags                  = flag.NewFlagSet(cmdNameSign, flag.ContinueOnError)
+		flagKey                = flags.String(flagNameKey, "", "")
+		flagSumfile            = flags.String(flagNameSumfile, "", "")
+		flagSumfilePerWorkflow = flags.Bool(flagNameSumfilePerWorkflow, false, "")
+	)
+
+	flags.Usage = func() { fmt.Fprintln(os.Stderr) }
+	if err := flags.Parse(argv); err != nil {
+		return errUsage
+	}
+
+	args := flags.Args()
+	if len(args) > 1 {
+		return errUsage
+	}
+
+	if *flagKey == "" {
+		return fmt.Errorf("the -%s flag is required", flagNameKey)
+	}
+
+	target, err := getTarget(args)
+	if err != nil {
+		return err
+	}
+
+	sumfile, err := getSumfile(*flagSumfile, *flagSumfilePerWorkflow)
+	if err != nil {
+		return err
+	}
+
+	key, err := os.ReadFile(*flagKey)
+	if err != nil {
+		return errors.Join(errUnexpected, err)
+	}
+
+	repo, err := os.OpenRoot(target)
+	if err != nil {
+		return errors.Join(errUnexpected, err)
+	}
+
+	cfg := ghasum.Config{
+		Repo:               repo.FS(),
+		Path:               target,
+		Sumfile:            sumfile,
+		SumfilePerWorkflow: *flagSumfilePerWorkflow,
+	}
+
+	if err := ghasum.Sign(&cfg, key); err != nil {
+		return errors.Join(errUnexpected, err)
+	}
+
+	tracked := ghasum.DefaultSumfile(cfg.Repo) + ".sig"
+	switch {
+	case *flagSumfilePerWorkflow:
+		tracked = "the " + path.Join(gha.WorkflowsDir(cfg.Repo), "*.sum.sig") + " files"
+	case sumfile != "":
+		tracked = sumfile + ".sig"
+	}
+
+	fmt.Printf(`Ok
+
+Next:
+1. Track %s with git.
+2. Use "ghasum verify -signers" in the project's workflows.
+`, tracked)
+	return nil
+}
+
+func helpSign() string {
+	return `usage: ghasum sign -key file [flags] [target]
+
+Create a detached signature for the gha.sum file of the target. If no target is
+provided it will default to the current working directory. The signature is
+written next to the gha.sum file, as gha.sum.sig. If ghasum is not yet
+initialized this command errors (see "ghasum help init").
+
+The signature is created using an SSH key in the same format as "ssh-keygen -Y
+sign" with the namespace "ghasum". Signatures can be verified using:
+
+    ghasum verify -signers allowed_signers [target]
+
+See "ghasum help verify" for more information.
+
+Signatures are not updated automatically. After updating the checksums (see
+"ghasum help update") the gha.sum file has to be signed again.
+
+The available flags are:
+
+    -key file
+        The private SSH key to sign with. The key must not be protected by a
+        passphrase. Required.
+    -sumfile path
+        The path to the gha.sum file, relative to the target.
+        Defaults to .github/workflows/gha.sum.
+    -sumfile-per-workflow
+        Sign the separate checksum file of every workflow, located next to the
+        workflow it belongs to (e.g. ci.yml.sum for ci.yml). Cannot be used
+        together with -sumfile.
+`
+}
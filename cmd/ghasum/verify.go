@@ -18,30 +18,54 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/chains-project/ghasum/internal/cache"
 	"github.com/chains-project/ghasum/internal/ghasum"
+	"github.com/chains-project/ghasum/internal/github"
 )
 
 func cmdVerify(argv []string) error {
 	var (
-		flags            = flag.NewFlagSet(cmdNameVerify, flag.ContinueOnError)
-		flagCache        = flags.String(flagNameCache, "", "")
-		flagNoCache      = flags.Bool(flagNameNoCache, false, "")
-		flagNoEvict      = flags.Bool(flagNameNoEvict, false, "")
-		flagNoTransitive = flags.Bool(flagNameNoTransitive, false, "")
-		flagOffline      = flags.Bool(flagNameOffline, false, "")
+		flags                  = flag.NewFlagSet(cmdNameVerify, flag.ContinueOnError)
+		flagCache              = flags.String(flagNameCache, "", "")
+		flagDebug              = flags.Bool(flagNameDebug, false, "")
+		flagFetcher            = flags.String(flagNameFetcher, "", "")
+		flagGitHubConnect      = flags.Bool(flagNameGitHubConnect, false, "")
+		flagGitHubURL          = flags.String(flagNameGitHubURL, "", "")
+		flagMetadataTTL        = flags.Duration(flagNameMetadataTTL, 0, "")
+		flagMinAge             = new(duration)
+		flagMirror             = flags.String(flagNameMirror, "", "")
+		flagNoCache            = flags.Bool(flagNameNoCache, false, "")
+		flagNoEvict            = flags.Bool(flagNameNoEvict, false, "")
+		flagNoTransitive       = flags.Bool(flagNameNoTransitive, false, "")
+		flagOffline            = flags.Bool(flagNameOffline, false, "")
+		flagReplace            = make(replacements)
+		flagRequireSigned      = flags.String(flagNameRequireSigned, "", "")
+		flagRev                = flags.String(flagNameRev, "", "")
+		flagRewrite            = make(rewrites)
+		flagSigners            = flags.String(flagNameSigners, "", "")
+		flagSumfile            = flags.String(flagNameSumfile, "", "")
+		flagSumfilePerWorkflow = flags.Bool(flagNameSumfilePerWorkflow, false, "")
+		flagSumfileRev         = flags.String(flagNameSumfileRev, "", "")
+		flagVerbose            = flags.Bool(flagNameVerbose, false, "")
 	)
 
+	flags.Var(flagReplace, flagNameReplace, "")
+	flags.Var(flagMinAge, flagNameMinAge, "")
+	flags.Var(flagRewrite, flagNameRewrite, "")
 	flags.Usage = func() { fmt.Fprintln(os.Stderr) }
 	if err := flags.Parse(argv); err != nil {
 		return errUsage
 	}
 
+	setVerbosity(*flagVerbose, *flagDebug)
+
 	args := flags.Args()
 	if len(args) > 1 {
 		return errUsage
@@ -52,6 +76,11 @@ func cmdVerify(argv []string) error {
 		return err
 	}
 
+	sumfile, err := getSumfile(*flagSumfile, *flagSumfilePerWorkflow)
+	if err != nil {
+		return err
+	}
+
 	var job string
 	if i := strings.LastIndexByte(target, 0x3A); i > 1 {
 		job = target[i+1:]
@@ -59,18 +88,46 @@ func cmdVerify(argv []string) error {
 	}
 
 	stat, err := os.Stat(target)
-	if err != nil {
+	switch {
+	case err == nil:
+	case errors.Is(err, fs.ErrNotExist) && *flagRev != "":
+		// the target workflow may only exist at the revision
+	default:
 		return errors.Join(errUnexpected, err)
 	}
 
 	var workflow string
-	if !stat.IsDir() {
+	if stat == nil || !stat.IsDir() {
 		repo := path.Join(path.Dir(target), "..", "..")
 		workflow, _ = filepath.Rel(repo, target)
 		workflow = strings.ReplaceAll(workflow, string(filepath.Separator), "/")
 		target = repo
 	}
 
+	repo, err := getRepo(target, *flagRev)
+	if err != nil {
+		return err
+	}
+
+	settings, err := configure(flags, repo)
+	if err != nil {
+		return err
+	}
+
+	if err = settings.addReplacements(flagReplace); err != nil {
+		return err
+	}
+
+	host, api, err := getGitHub(*flagGitHubURL)
+	if err != nil {
+		return err
+	}
+
+	minAges, err := settings.getMinAges(time.Duration(*flagMinAge))
+	if err != nil {
+		return err
+	}
+
 	c, err := cache.New(
 		cache.WithLocation(*flagCache),
 		cache.WithEviction(!*flagNoEvict),
@@ -80,19 +137,56 @@ func cmdVerify(argv []string) error {
 		return errors.Join(errCache, err)
 	}
 
-	repo, err := os.OpenRoot(target)
-	if err != nil {
-		return errors.Join(errUnexpected, err)
+	var sumfileRepo fs.FS
+	if *flagSumfileRev != "" {
+		sumfileRepo, err = getRevision(target, *flagSumfileRev)
+		if err != nil {
+			return err
+		}
+	}
+
+	var signers []byte
+	if *flagSigners != "" {
+		signers, err = os.ReadFile(*flagSigners)
+		if err != nil {
+			return errors.Join(errUnexpected, err)
+		}
+	}
+
+	var commitSigners map[string][]byte
+	if *flagRequireSigned != "" {
+		commitSigners, err = getCommitSigners(*flagRequireSigned)
+		if err != nil {
+			return err
+		}
 	}
 
 	cfg := ghasum.Config{
-		Repo:       repo.FS(),
-		Path:       target,
-		Workflow:   workflow,
-		Job:        job,
-		Cache:      c,
-		Offline:    *flagOffline,
-		Transitive: !(*flagNoTransitive),
+		Repo:               repo,
+		Path:               target,
+		Sumfile:            sumfile,
+		SumfilePerWorkflow: *flagSumfilePerWorkflow,
+		SumfileRepo:        sumfileRepo,
+		Signers:            signers,
+		CommitSigners:      commitSigners,
+		Workflow:           workflow,
+		Job:                job,
+		Cache:              c,
+		Offline:            *flagOffline,
+		Transitive:         !(*flagNoTransitive),
+		Host:               host,
+		API:                api,
+		Fallback:           *flagGitHubConnect,
+		Rewrites:           github.Rewrites(flagRewrite),
+		MetadataTTL:        *flagMetadataTTL,
+		MinAge:             time.Duration(*flagMinAge),
+		MinAges:            minAges,
+		Replace:            flagReplace,
+		Progress:           getProgress(),
+	}
+
+	if err = setFetcher(&cfg, *flagFetcher, *flagMirror, true); err != nil {
+		return err
 	}
 
 	report, err := ghasum.Verify(&cfg)
@@ -105,6 +199,13 @@ func cmdVerify(argv []string) error {
 }
 
 func reportVerify(report *ghasum.VerifyReport) error {
+	if cnt := len(report.Warnings); cnt > 0 {
+		fmt.Printf("%d warning(s) occurred during validation:\n", cnt)
+		for _, warning := range report.Warnings {
+			fmt.Println("  " + warning)
+		}
+	}
+
 	if cnt := len(report.Problems); cnt > 0 {
 		var sb strings.Builder
 
@@ -156,7 +257,38 @@ The available flags are:
     -cache dir
         The location of the cache directory. This is where ghasum stores and
         looks up repositories it needs.
-        Defaults to a directory named .ghasum in the user's home directory.
+        Defaults to $XDG_CACHE_HOME/ghasum, or a directory named .ghasum in the
+        user's home directory if it is unset.
+    -debug
+        Log debug messages to stderr in addition to those of -v, such as every
+        attempt to fetch a repository.
+    -fetcher name
+        The method used to fetch repositories, one of "go-git", "git" (using
+        the system git binary and its configuration), or "mirror" (see
+        -mirror).
+        Defaults to "go-git".
+    -github-connect
+        Fetch actions used without an explicit host from github.com if their
+        repository does not exist on the GitHub instance given by -github-url,
+        like a GitHub Enterprise Server does with GitHub Connect.
+    -github-url url
+        The URL of the GitHub instance, e.g. a GitHub Enterprise Server, that
+        houses actions used without an explicit host.
+        Defaults to $GITHUB_SERVER_URL, or https://github.com if it is unset.
+    -metadata-ttl duration
+        How long the metadata of repositories, such as whether they moved, is
+        kept in the cache before it is looked up again (e.g. 1h). With -offline
+        the metadata in the cache is used regardless of its age.
+        Defaults to 24h.
+    -min-age duration
+        Report actions whose commit, or tag if it was created later, is younger
+        than the given age (e.g. 7d or 12h). Can be overridden for specific
+        actions in the min-age mapping of a configuration file (see "ghasum
+        help config"), e.g. "owner/project: 14d" or "owner/project@v1: 0".
+    -mirror dir
+        The directory to read repositories from with "-fetcher mirror". The
+        repository owner/project is read from the (bare) git repository at
+        owner/project.git or owner/project in the directory.
     -no-cache
         Disable the use of the cache. Makes the -cache flag ineffective.
     -no-evict
@@ -166,5 +298,44 @@ The available flags are:
     -offline
         Run without fetching repositories from the internet, verify exclusively
         against the cache. If the cache is missing an entry it causes an error.
+        Commits that actions are pinned to are not checked for impostors.
+    -replace owner/project[@ref]=dir
+        Use the local directory dir instead of the repository of the action
+        owner/project at ref, or at any ref if @ref is omitted. Can be used
+        multiple times. The checksums of replaced actions are not
+        verified. Can also be configured (see "ghasum help config").
+    -require-signed dir
+        Require that the commit or annotated tag of every action is signed by a
+        key of its owner. The keys are read from the file named after the owner
+        in the given directory (e.g. dir/actions), in the OpenSSH allowed
+        signers format (see ssh-keygen(1)), which may also contain ASCII-armored
+        OpenPGP public keys.
+    -rev rev
+        Use the target at the given git revision (e.g. a branch, tag, or commit
+        SHA) instead of its working tree, including the gha.sum file. The target
+        must be a git repository.
+    -rewrite from=to
+        Use the URL prefix to instead of from for repositories and the API, in
+        the style of git's url.<base>.insteadOf (e.g. to use an internal
+        mirror). The longest matching prefix is used. Can be used multiple
+        times.
+    -signers file
+        Require a valid signature for the gha.sum file (see "ghasum help sign")
+        made by a key in the given file. The file must be in the OpenSSH
+        allowed signers format, see ssh-keygen(1).
+    -sumfile path
+        The path to the gha.sum file, relative to the target.
+        Defaults to .github/workflows/gha.sum.
+    -sumfile-per-workflow
+        Use a separate checksum file for every workflow, located next to the
+        workflow it belongs to (e.g. ci.yml.sum for ci.yml). Cannot be used
+        together with -sumfile.
+    -sumfile-rev rev
+        Read the gha.sum file from the given git revision (e.g. a branch, tag,
+        or commit SHA) of the target instead of from the target itself. Useful
+        to verify changes against the checksums on a protected branch.
+    -v
+        Log what is being done to stderr, such as which actions are fetched and
+        checksummed and how long that took.
 `
 }
@@ -110,6 +110,22 @@ func (c *Cache) Init() error {
 	return nil
 }
 
+// DefaultLocation returns the default location of the cache. This is a
+// directory named ghasum in $XDG_CACHE_HOME if it is set, or a directory named
+// .ghasum in the user's home directory otherwise.
+func DefaultLocation() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "ghasum"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home directory: %v", err)
+	}
+
+	return filepath.Join(home, ".ghasum"), nil
+}
+
 // Path returns the path to the cache on the file system.
 func (c *Cache) Path() string {
 	return c.path
@@ -117,8 +133,8 @@ func (c *Cache) Path() string {
 
 // New creates an uninitialized cache.
 //
-// If location is an empty string the location will default to the user's home
-// directory.
+// If location is an empty string the location will default to
+// [DefaultLocation].
 //
 // If ephemeral is set the cache will be located in a unique directory in the
 // system's temporary directory (and the given location is ignored).
@@ -133,12 +149,12 @@ func New(options ...Option) (Cache, error) {
 	case opts.Ephemeral:
 		c.ephemeral = true
 	case opts.Location == "":
-		home, err := os.UserHomeDir()
+		location, err := DefaultLocation()
 		if err != nil {
-			return c, fmt.Errorf("could not get home directory: %v", err)
+			return c, err
 		}
 
-		c.path = filepath.Join(home, ".ghasum")
+		c.path = location
 	default:
 		c.path = opts.Location
 	}
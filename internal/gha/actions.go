@@ -30,6 +30,8 @@ type workflowFile struct {
 	content []byte
 }
 
+const dockerfileName = "Dockerfile"
+
 func actionsInManifest(manifest manifest) ([]GitHubAction, error) {
 	unique := make(map[string]GitHubAction, 0)
 	err := actionsInSteps(manifest.Runs.Steps, unique)
@@ -40,6 +42,54 @@ func actionsInManifest(manifest manifest) ([]GitHubAction, error) {
 	return slices.Collect(maps.Values(unique)), nil
 }
 
+func imagesInDockerfile(dockerfile dockerfile) ([]GitHubAction, error) {
+	unique := make(map[string]GitHubAction, 0)
+	for _, image := range dockerfile.From {
+		action, err := parseUses(DockerPrefix + image)
+		if !errors.Is(err, ErrDockerUses) {
+			return nil, ErrInvalidFrom
+		}
+
+		action.Kind = BaseImage
+		unique[actionId(action)] = action
+	}
+
+	return slices.Collect(maps.Values(unique)), nil
+}
+
+func imagesInManifest(repo fs.FS, dir string, manifest manifest) ([]GitHubAction, error) {
+	image := manifest.Runs.Image
+	if image == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(image, DockerPrefix) {
+		action, err := parseUses(image)
+		if !errors.Is(err, ErrDockerUses) {
+			return nil, fmt.Errorf("invalid image %q: %v", image, err)
+		}
+
+		action.Kind = BaseImage
+		return []GitHubAction{action}, nil
+	}
+
+	return imagesInRepoDockerfile(repo, path.Join(dir, image))
+}
+
+func imagesInRepoDockerfile(repo fs.FS, path string) ([]GitHubAction, error) {
+	data, err := dockerfileInRepo(repo, path)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := parseDockerfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse Dockerfile at %q: %w", path, err)
+	}
+
+	return imagesInDockerfile(d)
+}
+
 func actionsInWorkflows(workflows []workflow) ([]GitHubAction, error) {
 	unique := make(map[string]GitHubAction, 0)
 	for _, workflow := range workflows {
@@ -82,7 +132,7 @@ func actionsInSteps(steps []step, m map[string]GitHubAction) error {
 		case errors.Is(err, ErrLocalAction):
 			action.Kind = LocalAction
 		case errors.Is(err, ErrDockerUses):
-			continue
+			action.Kind = DockerImage
 		default:
 			return err
 		}
@@ -94,43 +144,58 @@ func actionsInSteps(steps []step, m map[string]GitHubAction) error {
 }
 
 func actionId(action GitHubAction) string {
-	return fmt.Sprintf("%s%s%s%s", action.Owner, action.Project, action.Path, action.Ref)
+	return fmt.Sprintf("%s%s%s%s%s", action.Host, action.Owner, action.Project, action.Path, action.Ref)
 }
 
 func workflowsInRepo(repo fs.FS) ([]workflowFile, error) {
 	workflows := make([]workflowFile, 0)
-	walk := func(entryPath string, entry fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
+	walk := func(root string) fs.WalkDirFunc {
+		return func(entryPath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
 
-		if entry.IsDir() {
-			if entryPath == WorkflowsPath {
+			if entry.IsDir() {
+				if entryPath == root {
+					return nil
+				} else {
+					return fs.SkipDir
+				}
+			}
+
+			if ext := path.Ext(entryPath); ext != ".yml" && ext != ".yaml" {
 				return nil
-			} else {
-				return fs.SkipDir
 			}
-		}
 
-		if ext := path.Ext(entryPath); ext != ".yml" && ext != ".yaml" {
+			data, err := workflowInRepo(repo, entryPath)
+			if err != nil {
+				return err
+			}
+
+			workflows = append(workflows, workflowFile{
+				content: data,
+				path:    entryPath,
+			})
+
 			return nil
 		}
+	}
 
-		data, err := workflowInRepo(repo, entryPath)
-		if err != nil {
-			return err
+	dirs := make([]string, 0, len(WorkflowsPaths))
+	for _, dir := range WorkflowsPaths {
+		if _, err := fs.Stat(repo, dir); err == nil {
+			dirs = append(dirs, dir)
 		}
+	}
 
-		workflows = append(workflows, workflowFile{
-			content: data,
-			path:    entryPath,
-		})
-
-		return nil
+	if len(dirs) == 0 {
+		dirs = append(dirs, WorkflowsPath)
 	}
 
-	if err := fs.WalkDir(repo, WorkflowsPath, walk); err != nil {
-		return nil, fmt.Errorf("failed to find workflows: %v", err)
+	for _, dir := range dirs {
+		if err := fs.WalkDir(repo, dir, walk(dir)); err != nil {
+			return nil, fmt.Errorf("failed to find workflows: %v", err)
+		}
 	}
 
 	return workflows, nil
@@ -146,6 +211,15 @@ func workflowInRepo(repo fs.FS, path string) ([]byte, error) {
 	return data, nil
 }
 
+func dockerfileInRepo(repo fs.FS, path string) ([]byte, error) {
+	data, err := fs.ReadFile(repo, path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open Dockerfile at %q: %v", path, err)
+	}
+
+	return data, nil
+}
+
 func manifestInRepo(repo fs.FS, dir string) ([]byte, error) {
 	manifest := path.Join(dir, "action.yml")
 	if file, err := repo.Open(manifest); err == nil {
@@ -159,7 +233,7 @@ func manifestInRepo(repo fs.FS, dir string) ([]byte, error) {
 		return data, nil
 	}
 
-	manifest = path.Join(dir, "Dockerfile")
+	manifest = path.Join(dir, dockerfileName)
 	if _, err := repo.Open(manifest); err == nil {
 		return nil, ErrDockerfileManifest
 	}
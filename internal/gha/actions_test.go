@@ -65,6 +65,18 @@ func TestActionsInManifest(t *testing.T) {
 				},
 				want: 1,
 			},
+			"one step with Docker uses": {
+				manifest: manifest{
+					Runs: runs{
+						Steps: []step{
+							{
+								Uses: "docker://alpine:3.8",
+							},
+						},
+					},
+				},
+				want: 1,
+			},
 			"multiple step with unique uses": {
 				manifest: manifest{
 					Runs: runs{
@@ -576,6 +588,69 @@ func TestWorkflowsInRepo(t *testing.T) {
 				},
 				want: []workflowFile{},
 			},
+			"forgejo workflow": {
+				fs: map[string]mockFsEntry{
+					".forgejo": {
+						Dir: true,
+						Children: map[string]mockFsEntry{
+							"workflows": {
+								Dir: true,
+								Children: map[string]mockFsEntry{
+									"example.yml": {
+										Content: []byte(workflowWithJobsWithSteps),
+									},
+								},
+							},
+						},
+					},
+				},
+				want: []workflowFile{
+					{
+						content: []byte(workflowWithJobsWithSteps),
+						path:    ".forgejo/workflows/example.yml",
+					},
+				},
+			},
+			"github and gitea workflows": {
+				fs: map[string]mockFsEntry{
+					".github": {
+						Dir: true,
+						Children: map[string]mockFsEntry{
+							"workflows": {
+								Dir: true,
+								Children: map[string]mockFsEntry{
+									"github.yml": {
+										Content: []byte(workflowWithJobsWithSteps),
+									},
+								},
+							},
+						},
+					},
+					".gitea": {
+						Dir: true,
+						Children: map[string]mockFsEntry{
+							"workflows": {
+								Dir: true,
+								Children: map[string]mockFsEntry{
+									"gitea.yml": {
+										Content: []byte(workflowWithJobsWithSteps),
+									},
+								},
+							},
+						},
+					},
+				},
+				want: []workflowFile{
+					{
+						content: []byte(workflowWithJobsWithSteps),
+						path:    ".github/workflows/github.yml",
+					},
+					{
+						content: []byte(workflowWithJobsWithSteps),
+						path:    ".gitea/workflows/gitea.yml",
+					},
+				},
+			},
 		}
 
 		for name, tt := range testCases {
@@ -20,9 +20,17 @@ var (
 	// ErrDockerUses is the error used when a uses value is for a Docker action.
 	ErrDockerUses = errors.New("uses is a Docker Hub/Container Registry action")
 
+	// ErrInvalidFrom is the error used for an invalid FROM instruction in a
+	// Dockerfile.
+	ErrInvalidFrom = errors.New("invalid FROM instruction in Dockerfile")
+
 	// ErrInvalidUses is the error used for an invalid uses value.
 	ErrInvalidUses = errors.New("invalid uses value")
 
+	// ErrInvalidUsesHost is the error used for a uses value with an invalid
+	// host.
+	ErrInvalidUsesHost = errors.New("invalid host in uses")
+
 	// ErrInvalidUsesRepo is the error used for a uses value with an invalid
 	// repository.
 	ErrInvalidUsesRepo = errors.New("invalid repository in uses")
@@ -19,10 +19,16 @@ import (
 	"fmt"
 	"io/fs"
 	"path"
+	"strings"
 )
 
 // A GitHubAction identifies a specific version of a GitHub Action.
 type GitHubAction struct {
+	// Host is the host of the forge (e.g. a Forgejo or Gitea instance) that
+	// houses the GitHub Action. If this has the zero value the GitHub Action is
+	// housed on the GitHub instance the workflow runs on.
+	Host string
+
 	// Owner is the GitHub user or organization that owns the repository that
 	// houses the GitHub Action.
 	Owner string
@@ -32,10 +38,15 @@ type GitHubAction struct {
 	Project string
 
 	// Path is the path of the action inside the GitHub repository.
+	//
+	// For a [DockerImage] or [BaseImage] this is the name of the image instead.
 	Path string
 
 	// Ref is the git ref (branch, tag, commit SHA), also known as version, of the
 	// GitHub Action.
+	//
+	// For a [DockerImage] or [BaseImage] this is the tag or digest of the image
+	// instead.
 	Ref string
 
 	// Kind is the [ActionKind] of the GitHub Action.
@@ -77,10 +88,38 @@ const (
 	// A reusable workflow is a workflow in a repository with the appropriate
 	// workflow trigger. These are used in the `uses:` value of workflow jobs.
 	ReusableWorkflow
+
+	// DockerImage represent a GitHub Actions component that is a Docker image.
+	//
+	// A Docker image is an image in a container registry, identified by a tag
+	// or digest. These are used in the `uses:` value of steps with the
+	// docker:// prefix.
+	DockerImage
+
+	// BaseImage represent a GitHub Actions component that is the Docker image an
+	// action is based on.
+	//
+	// A base image is an image in a container registry, identified by a tag or
+	// digest. These are used in the `FROM` instructions of the Dockerfile of an
+	// action or in the `runs.image` value of an action manifest.
+	BaseImage
 )
 
+// DockerPrefix is the prefix of `uses:` values that refer to a Docker image.
+const DockerPrefix = "docker://"
+
+const workflowsDir = "workflows"
+
 // WorkflowsPath is the relative path to the GitHub Actions workflow directory.
-var WorkflowsPath = path.Join(".github", "workflows")
+var WorkflowsPath = path.Join(".github", workflowsDir)
+
+// WorkflowsPaths are the relative paths to the workflow directories of GitHub
+// Actions and compatible platforms (Forgejo and Gitea), in order of preference.
+var WorkflowsPaths = []string{
+	WorkflowsPath,
+	path.Join(".forgejo", workflowsDir),
+	path.Join(".gitea", workflowsDir),
+}
 
 // RepoActions extracts the GitHub Actions used in the repository at the given
 // file system hierarchy.
@@ -108,6 +147,35 @@ func RepoActions(repo fs.FS) ([]GitHubAction, error) {
 	return actions, nil
 }
 
+// WorkflowsDir returns the first of the [WorkflowsPaths] that exists in the
+// repository at the given file system hierarchy, or [WorkflowsPath] if none of
+// them exist.
+func WorkflowsDir(repo fs.FS) string {
+	for _, dir := range WorkflowsPaths {
+		if stat, err := fs.Stat(repo, dir); err == nil && stat.IsDir() {
+			return dir
+		}
+	}
+
+	return WorkflowsPath
+}
+
+// Workflows returns the paths of the workflows in the repository at the given
+// file system hierarchy.
+func Workflows(repo fs.FS) ([]string, error) {
+	rawWorkflows, err := workflowsInRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(rawWorkflows))
+	for i, rawWorkflow := range rawWorkflows {
+		paths[i] = rawWorkflow.path
+	}
+
+	return paths, nil
+}
+
 // WorkflowActions extracts the GitHub Actions used in the specified workflow at
 // the given file system hierarchy.
 func WorkflowActions(repo fs.FS, path string) ([]GitHubAction, error) {
@@ -162,10 +230,14 @@ func JobActions(repo fs.FS, path, name string) ([]GitHubAction, error) {
 
 // ManifestActions extracts the GitHub Actions used in the manifest in the
 // specified directory in the given file system hierarchy.
-func ManifestActions(repo fs.FS, path string) ([]GitHubAction, error) {
-	data, err := manifestInRepo(repo, path)
+//
+// This includes the base images of the action if it is a Docker action, either
+// from the `FROM` instructions of its Dockerfile or from its `runs.image`.
+// If the Dockerfile cannot be parsed the error wraps [ErrInvalidFrom].
+func ManifestActions(repo fs.FS, dir string) ([]GitHubAction, error) {
+	data, err := manifestInRepo(repo, dir)
 	if errors.Is(err, ErrDockerfileManifest) {
-		return nil, nil
+		return imagesInRepoDockerfile(repo, path.Join(dir, dockerfileName))
 	} else if err != nil {
 		return nil, err
 	}
@@ -180,14 +252,32 @@ func ManifestActions(repo fs.FS, path string) ([]GitHubAction, error) {
 		return nil, fmt.Errorf("could not extract actions from manifest at: %v", err)
 	}
 
-	return actions, nil
+	images, err := imagesInManifest(repo, dir, m)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract images from manifest: %w", err)
+	}
+
+	return append(actions, images...), nil
 }
 
 func (a GitHubAction) String() string {
+	if a.Kind.IsImage() {
+		if strings.ContainsRune(a.Ref, ':') {
+			return fmt.Sprintf("%s%s@%s", DockerPrefix, a.Path, a.Ref)
+		} else {
+			return fmt.Sprintf("%s%s:%s", DockerPrefix, a.Path, a.Ref)
+		}
+	}
+
+	var host string
+	if a.Host != "" {
+		host = "https://" + a.Host + "/"
+	}
+
 	if a.Path == "" {
-		return fmt.Sprintf("%s/%s@%s", a.Owner, a.Project, a.Ref)
+		return fmt.Sprintf("%s%s/%s@%s", host, a.Owner, a.Project, a.Ref)
 	} else {
-		return fmt.Sprintf("%s/%s/%s@%s", a.Owner, a.Project, a.Path, a.Ref)
+		return fmt.Sprintf("%s%s/%s/%s@%s", host, a.Owner, a.Project, a.Path, a.Ref)
 	}
 }
 
@@ -197,11 +287,19 @@ func (k ActionKind) String() string {
 		return "action"
 	case ReusableWorkflow, LocalReusableWorkflow:
 		return "reusable workflow"
+	case DockerImage:
+		return "docker image"
+	case BaseImage:
+		return "base image"
 	default:
 		panic("unknown action kind " + string(k))
 	}
 }
 
+func (k ActionKind) IsImage() bool {
+	return k == DockerImage || k == BaseImage
+}
+
 func (k ActionKind) IsLocal() bool {
 	return k == LocalAction || k == LocalReusableWorkflow
 }
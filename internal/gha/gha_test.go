@@ -232,6 +232,71 @@ func TestRealisticRepository(t *testing.T) {
 	}
 }
 
+func TestWorkflows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Repository", func(t *testing.T) {
+		t.Parallel()
+
+		fs := map[string]mockFsEntry{
+			".github": {
+				Dir: true,
+				Children: map[string]mockFsEntry{
+					"workflows": {
+						Dir: true,
+						Children: map[string]mockFsEntry{
+							"workflow.yml": {
+								Content: []byte(workflowWithJobWithSteps),
+							},
+							"workflow.yaml": {
+								Content: []byte(workflowWithJobsWithSteps),
+							},
+							"workflow.yml.sum": {
+								Content: []byte("version 1\n"),
+							},
+							"nested": {
+								Dir: true,
+								Children: map[string]mockFsEntry{
+									"workflow.yml": {
+										Content: []byte(workflowWithJobWithSteps),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		repo, err := mockRepo(fs)
+		if err != nil {
+			t.Fatalf("Could not initialize file system: %+v", err)
+		}
+
+		got, err := Workflows(repo)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		want := []string{
+			".github/workflows/workflow.yaml",
+			".github/workflows/workflow.yml",
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Unexpected workflows (got %v, want %v)", got, want)
+		}
+	})
+
+	t.Run("No workflows", func(t *testing.T) {
+		t.Parallel()
+
+		repo := memoryfs.New()
+		if _, err := Workflows(repo); err == nil {
+			t.Fatal("Unexpected success")
+		}
+	})
+}
+
 func TestWorkflowActions(t *testing.T) {
 	t.Parallel()
 
@@ -754,6 +819,54 @@ func TestManifestActions(t *testing.T) {
 			path:    "nested",
 			wantErr: false,
 		},
+		"manifest with registry image": {
+			fs: map[string]mockFsEntry{
+				"action.yml": {
+					Content: []byte(manifestWithImage),
+				},
+			},
+			path:    "",
+			wantErr: false,
+		},
+		"manifest with Dockerfile image": {
+			fs: map[string]mockFsEntry{
+				"action.yml": {
+					Content: []byte(manifestWithImageDockerfile),
+				},
+				"Dockerfile": {
+					Content: []byte(manifestDockerfile),
+				},
+			},
+			path:    "",
+			wantErr: false,
+		},
+		"manifest with invalid registry image": {
+			fs: map[string]mockFsEntry{
+				"action.yml": {
+					Content: []byte(manifestWithInvalidImage),
+				},
+			},
+			path:    "",
+			wantErr: true,
+		},
+		"manifest with missing Dockerfile image": {
+			fs: map[string]mockFsEntry{
+				"action.yml": {
+					Content: []byte(manifestWithImageDockerfile),
+				},
+			},
+			path:    "",
+			wantErr: true,
+		},
+		"invalid Dockerfile manifest": {
+			fs: map[string]mockFsEntry{
+				"Dockerfile": {
+					Content: []byte("FROM"),
+				},
+			},
+			path:    "",
+			wantErr: true,
+		},
 		"manifest with syntax error": {
 			fs: map[string]mockFsEntry{
 				"action.yml": {
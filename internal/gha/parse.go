@@ -16,6 +16,7 @@ package gha
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"go.yaml.in/yaml/v3"
@@ -27,6 +28,7 @@ type (
 	}
 
 	runs struct {
+		Image string `yaml:"image"`
 		Steps []step `yaml:"steps"`
 	}
 
@@ -42,8 +44,111 @@ type (
 	step struct {
 		Uses string `yaml:"uses"`
 	}
+
+	dockerfile struct {
+		From []string
+	}
 )
 
+func parseDockerfile(data []byte) (dockerfile, error) {
+	var d dockerfile
+
+	args := make(map[string]string, 0)
+	stages := make(map[string]struct{}, 0)
+	seenFrom := false
+
+	for _, instruction := range dockerfileInstructions(data) {
+		fields := strings.Fields(instruction)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			// only ARGs declared before the first FROM can be used in FROM
+			if seenFrom {
+				continue
+			}
+
+			for _, arg := range fields[1:] {
+				name, value, _ := strings.Cut(arg, "=")
+				args[name] = strings.Trim(value, `"'`)
+			}
+		case "FROM":
+			seenFrom = true
+
+			fields = fields[1:]
+			for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+				fields = fields[1:]
+			}
+
+			if len(fields) != 1 && (len(fields) != 3 || !strings.EqualFold(fields[1], "AS")) {
+				return d, ErrInvalidFrom
+			}
+
+			image, ok := expand(fields[0], args)
+			if !ok {
+				return d, ErrInvalidFrom
+			}
+
+			if _, isStage := stages[strings.ToLower(image)]; !isStage && image != "scratch" {
+				d.From = append(d.From, image)
+			}
+
+			if len(fields) == 3 {
+				stages[strings.ToLower(fields[2])] = struct{}{}
+			}
+		}
+	}
+
+	return d, nil
+}
+
+func dockerfileInstructions(data []byte) []string {
+	instructions := make([]string, 0)
+
+	var sb strings.Builder
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if continued, ok := strings.CutSuffix(line, "\\"); ok {
+			sb.WriteString(continued)
+			sb.WriteRune(' ')
+			continue
+		}
+
+		sb.WriteString(line)
+		instructions = append(instructions, sb.String())
+		sb.Reset()
+	}
+
+	if sb.Len() > 0 {
+		instructions = append(instructions, sb.String())
+	}
+
+	return instructions
+}
+
+func expand(s string, args map[string]string) (string, bool) {
+	ok := true
+	expanded := os.Expand(s, func(name string) string {
+		name, fallback, hasFallback := strings.Cut(name, ":-")
+		if value := args[name]; value != "" {
+			return value
+		} else if hasFallback {
+			return fallback
+		}
+
+		ok = false
+		return ""
+	})
+
+	return expanded, ok
+}
+
 func parseManifest(data []byte) (manifest, error) {
 	var m manifest
 	if err := yaml.Unmarshal(data, &m); err != nil {
@@ -70,8 +175,19 @@ func parseUses(uses string) (GitHubAction, error) {
 	case strings.HasPrefix(uses, "./"):
 		a.Path = uses
 		return a, ErrLocalAction
-	case strings.HasPrefix(uses, "docker://"):
-		return a, ErrDockerUses
+	case strings.HasPrefix(uses, DockerPrefix):
+		return parseDockerUses(uses)
+	}
+
+	// split "https://host/repo@ref" into "host" and "repo@ref"
+	if url, ok := strings.CutPrefix(uses, "https://"); ok {
+		host, rest, _ := strings.Cut(url, "/")
+		if host == "" || strings.ContainsRune(host, '@') {
+			return a, ErrInvalidUsesHost
+		}
+
+		a.Host = strings.ToLower(host)
+		uses = rest
 	}
 
 	// split "uses" into "repo"@"ref"
@@ -104,3 +220,23 @@ func parseUses(uses string) (GitHubAction, error) {
 	a.Project = project
 	return a, nil
 }
+
+func parseDockerUses(uses string) (GitHubAction, error) {
+	var a GitHubAction
+
+	// split "docker://image" into "image"(":"tag|"@"digest)
+	image := strings.TrimPrefix(uses, DockerPrefix)
+	if i := strings.IndexRune(image, '@'); i >= 0 {
+		a.Path, a.Ref = image[:i], image[i+1:]
+	} else if i := strings.LastIndexByte(image, ':'); i > strings.LastIndexByte(image, '/') {
+		a.Path, a.Ref = image[:i], image[i+1:]
+	} else {
+		a.Path, a.Ref = image, "latest"
+	}
+
+	if a.Path == "" || a.Ref == "" || strings.ContainsAny(a.Path+a.Ref, "@ ") {
+		return a, ErrInvalidUses
+	}
+
+	return a, ErrDockerUses
+}
@@ -17,6 +17,7 @@ package gha
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 	"testing/quick"
@@ -128,6 +129,34 @@ func TestParseUses(t *testing.T) {
 					Ref:     "V42",
 				},
 			},
+			"full URL, GitHub": {
+				uses: "https://github.com/foo/bar@v1",
+				want: GitHubAction{
+					Host:    "github.com",
+					Owner:   "foo",
+					Project: "bar",
+					Ref:     "v1",
+				},
+			},
+			"full URL, other host": {
+				uses: "https://code.forgejo.org/actions/checkout@v4",
+				want: GitHubAction{
+					Host:    "code.forgejo.org",
+					Owner:   "actions",
+					Project: "checkout",
+					Ref:     "v4",
+				},
+			},
+			"full URL, other host with path": {
+				uses: "https://Gitea.example.com/foo/bar/baz@v2",
+				want: GitHubAction{
+					Host:    "gitea.example.com",
+					Owner:   "foo",
+					Project: "bar",
+					Path:    "baz",
+					Ref:     "v2",
+				},
+			},
 		}
 
 		for name, tt := range testCases {
@@ -139,6 +168,10 @@ func TestParseUses(t *testing.T) {
 					t.Fatalf("Unexpected error: %+v", err)
 				}
 
+				if got, want := got.Host, tt.want.Host; got != want {
+					t.Errorf("Incorrect host (got %q, want %q)", got, want)
+				}
+
 				if got, want := got.Owner, tt.want.Owner; got != want {
 					t.Errorf("Incorrect owner (got %q, want %q)", got, want)
 				}
@@ -227,6 +260,14 @@ func TestParseUses(t *testing.T) {
 				uses: "foo//bar@baz",
 				want: ErrInvalidUsesPath,
 			},
+			"full URL without host": {
+				uses: "https:///foo/bar@baz",
+				want: ErrInvalidUsesHost,
+			},
+			"full URL without repository": {
+				uses: "https://code.forgejo.org",
+				want: ErrInvalidUses,
+			},
 		}
 
 		for name, tt := range testCases {
@@ -245,6 +286,90 @@ func TestParseUses(t *testing.T) {
 		}
 	})
 
+	t.Run("Docker examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			uses string
+			want GitHubAction
+		}
+
+		testCases := map[string]TestCase{
+			"Docker Hub image, tag": {
+				uses: "docker://alpine:3.8",
+				want: GitHubAction{
+					Path: "alpine",
+					Ref:  "3.8",
+				},
+			},
+			"Docker Hub image, no tag": {
+				uses: "docker://alpine",
+				want: GitHubAction{
+					Path: "alpine",
+					Ref:  "latest",
+				},
+			},
+			"Docker Hub image, digest": {
+				uses: "docker://alpine@sha256:9f1f1b2c",
+				want: GitHubAction{
+					Path: "alpine",
+					Ref:  "sha256:9f1f1b2c",
+				},
+			},
+			"registry image, no tag": {
+				uses: "docker://ghcr.io/OWNER/IMAGE_NAME",
+				want: GitHubAction{
+					Path: "ghcr.io/OWNER/IMAGE_NAME",
+					Ref:  "latest",
+				},
+			},
+			"registry with port, tag": {
+				uses: "docker://localhost:5000/foo/bar:v1",
+				want: GitHubAction{
+					Path: "localhost:5000/foo/bar",
+					Ref:  "v1",
+				},
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := parseUses(tt.uses)
+				if !errors.Is(err, ErrDockerUses) {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if want := tt.want; got != want {
+					t.Errorf("Incorrect result (got %+v, want %+v)", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid Docker examples", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]string{
+			"no image":         "docker://",
+			"no tag":           "docker://alpine:",
+			"no digest":        "docker://alpine@",
+			"multiple digests": "docker://alpine@sha256:foo@sha256:bar",
+		}
+
+		for name, uses := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := parseUses(uses)
+				if got, want := err, ErrInvalidUses; !errors.Is(got, want) {
+					t.Errorf("Incorrect error (got %q, want %q)", got, want)
+				}
+			})
+		}
+	})
+
 	t.Run("Arbitrary values", func(t *testing.T) {
 		t.Parallel()
 
@@ -591,3 +716,123 @@ func TestParseManifest(t *testing.T) {
 		}
 	})
 }
+
+func TestParseDockerfile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			in   string
+			want []string
+		}
+
+		testCases := map[string]TestCase{
+			"single stage": {
+				in:   manifestDockerfile,
+				want: []string{"docker.io/alpine:3.21.3"},
+			},
+			"no FROM": {
+				in:   "RUN echo 'Hello world!'\n",
+				want: []string{},
+			},
+			"lowercase instruction": {
+				in:   "from alpine:3.8\n",
+				want: []string{"alpine:3.8"},
+			},
+			"platform flag": {
+				in:   "FROM --platform=linux/amd64 alpine:3.8\n",
+				want: []string{"alpine:3.8"},
+			},
+			"multiple stages": {
+				in:   "FROM golang:1.26 AS build\nFROM alpine:3.8\n",
+				want: []string{"golang:1.26", "alpine:3.8"},
+			},
+			"stage reference": {
+				in:   "FROM alpine:3.8 AS base\nFROM base\nFROM BASE\n",
+				want: []string{"alpine:3.8"},
+			},
+			"scratch": {
+				in:   "FROM scratch\n",
+				want: []string{},
+			},
+			"comments": {
+				in:   "# FROM foo:bar\nFROM alpine:3.8\n",
+				want: []string{"alpine:3.8"},
+			},
+			"line continuation": {
+				in:   "FROM \\\n  alpine:3.8\n",
+				want: []string{"alpine:3.8"},
+			},
+			"global argument": {
+				in:   "ARG VERSION=3.8\nFROM alpine:$VERSION\nFROM golang:${VERSION}\n",
+				want: []string{"alpine:3.8", "golang:3.8"},
+			},
+			"global argument with quotes": {
+				in:   "ARG VERSION=\"3.8\"\nFROM alpine:${VERSION}\n",
+				want: []string{"alpine:3.8"},
+			},
+			"empty continuation": {
+				in:   "\\\n\nFROM alpine:3.8\n",
+				want: []string{"alpine:3.8"},
+			},
+			"argument with default": {
+				in:   "ARG VERSION\nFROM alpine:${VERSION:-3.8}\n",
+				want: []string{"alpine:3.8"},
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := parseDockerfile([]byte(tt.in))
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if want := tt.want; !slices.Equal(got.From, want) {
+					t.Errorf("Incorrect images (got %v, want %v)", got.From, want)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid examples", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]string{
+			"no image":           "FROM\n",
+			"only flags":         "FROM --platform=linux/amd64\n",
+			"extra arguments":    "FROM alpine:3.8 foo\n",
+			"missing stage name": "FROM alpine:3.8 AS\n",
+			"unknown argument":   "FROM alpine:${VERSION}\n",
+			"stage argument":     "FROM alpine:3.8\nARG VERSION=3.8\nFROM alpine:${VERSION}\n",
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := parseDockerfile([]byte(tt))
+				if got, want := err, ErrInvalidFrom; !errors.Is(got, want) {
+					t.Errorf("Incorrect error (got %v, want %q)", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("Arbitrary values", func(t *testing.T) {
+		t.Parallel()
+
+		noPanic := func(d []byte) bool {
+			_, _ = parseDockerfile(d)
+			return true
+		}
+
+		if err := quick.Check(noPanic, nil); err != nil {
+			t.Errorf("Parsing failed for: %v", err)
+		}
+	})
+}
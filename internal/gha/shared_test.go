@@ -54,6 +54,21 @@ runs:
 `
 	manifestDockerfile = `FROM docker.io/alpine:3.21.3
 ENTRYPOINT ["echo", "Hello world!"]
+`
+	manifestWithImage = `name: manifest with a registry image
+runs:
+  using: docker
+  image: docker://alpine:3.8
+`
+	manifestWithInvalidImage = `name: manifest with an invalid registry image
+runs:
+  using: docker
+  image: docker://
+`
+	manifestWithImageDockerfile = `name: manifest with a Dockerfile image
+runs:
+  using: docker
+  image: Dockerfile
 `
 
 	workflowWithNoJobs = `name: workflow with no jobs
@@ -15,23 +15,298 @@
 package ghasum
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"log/slog"
 	"maps"
 	"os"
 	"path"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/chains-project/ghasum/internal/cache"
 	"github.com/chains-project/ghasum/internal/checksum"
 	"github.com/chains-project/ghasum/internal/gha"
 	"github.com/chains-project/ghasum/internal/github"
+	"github.com/chains-project/ghasum/internal/oci"
+	"github.com/chains-project/ghasum/internal/osv"
+	"github.com/chains-project/ghasum/internal/signature"
 	"github.com/chains-project/ghasum/internal/sumfile"
+	"github.com/go-git/go-git/v5"
 )
 
-var ghasumPath = path.Join(gha.WorkflowsPath, "gha.sum")
+const (
+	imagesDir      = ".oci"
+	logKeyAction   = "action"
+	logKeyDuration = "duration"
+	logKeyErr      = "err"
+	mergeDriver    = "ghasum"
+	metadataStore  = ".metadata.json"
+	reachableStore = ".reachable.json"
+	revisionsStore = ".revisions.json"
+	signatureExt   = ".sig"
+)
+
+// age returns a description of how long ago the given time is.
+func age(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days ago", int(d.Hours()/24))
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%d hours ago", int(d.Hours()))
+	case d >= 2*time.Minute:
+		return fmt.Sprintf("%d minutes ago", int(d.Minutes()))
+	default:
+		return "a minute ago"
+	}
+}
+
+// days returns a description of the given duration, in days if it is a whole
+// number of days.
+func days(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+
+	return d.String()
+}
+
+func auditTags(cfg *Config, entries []sumfile.Entry) (AuditReport, error) {
+	var report AuditReport
+
+	byRepo := make(map[string][]sumfile.Entry, 0)
+	for _, entry := range entries {
+		if len(entry.ID) != 2 || strings.HasPrefix(entry.ID[0], gha.DockerPrefix) {
+			continue
+		}
+
+		byRepo[entry.ID[0]] = append(byRepo[entry.ID[0]], entry)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(byRepo)) {
+		parts := strings.Split(name, "/")
+		if len(parts) != 2 && len(parts) != 3 {
+			continue
+		}
+
+		action := gha.GitHubAction{Owner: parts[len(parts)-2], Project: parts[len(parts)-1]}
+		if len(parts) == 3 {
+			action.Host = parts[0]
+		}
+
+		tags, err := remoteTags(cfg, &action)
+		if err != nil {
+			return report, err
+		}
+
+		for _, entry := range byRepo[name] {
+			commit, ok := tags[entry.ID[1]]
+			if !ok {
+				continue
+			}
+
+			id := strings.Join(entry.ID, "@")
+			if entry.Commit == "" {
+				p := fmt.Sprintf("no commit recorded for %q", id)
+				report.Warnings = append(report.Warnings, Problem(p))
+				continue
+			}
+
+			report.Total += 1
+			if commit != entry.Commit {
+				p := fmt.Sprintf("tag moved for %q (from %s to %s)", id, entry.Commit, commit)
+				report.Problems = append(report.Problems, Problem(p))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func auditVulns(cfg *Config, actions *tree, db *osv.Database) AuditReport {
+	var report AuditReport
+
+	recorded := revisions(cfg)
+	listed := make(map[string]map[string]string, 0)
+	audited := make(map[string][]string, 0)
+	for path := range actions.Paths() {
+		action := path[len(path)-1]
+		if _, ok := replacement(cfg, &action); ok || action.Kind.IsImage() {
+			continue
+		}
+
+		if repo := repository(cfg, &action); repo.ID().Host != github.DefaultHost {
+			continue
+		}
+
+		id := strings.Join(entryID(&action), "@")
+		found, ok := audited[id]
+		if !ok {
+			known := versions(&action, recorded)
+			if len(known) == 0 && !cfg.Offline {
+				var err error
+				if known, err = tagged(cfg, &action, recorded, listed); err != nil {
+					report.Warnings = append(report.Warnings, Problem(err.Error()))
+				}
+			}
+
+			if len(known) == 0 {
+				p := fmt.Sprintf("could not determine the version of %q", id)
+				report.Warnings = append(report.Warnings, Problem(p))
+			}
+
+			found = advisories(db, &action, known)
+			audited[id] = found
+		}
+
+		if len(found) > 0 {
+			steps := make([]string, len(path))
+			for i, step := range path {
+				steps[i] = step.String()
+			}
+
+			p := fmt.Sprintf("%s is affected by %s (path: %s)", action, strings.Join(found, ", "), strings.Join(steps, " > "))
+			report.Problems = append(report.Problems, Problem(p))
+		}
+	}
+
+	report.Total = len(audited)
+	slices.Sort(report.Problems)
+
+	return report
+}
+
+// advisories returns the advisories that affect any of the given versions of
+// the given action, described by their ID and aliases.
+func advisories(db *osv.Database, action *gha.GitHubAction, versions []string) []string {
+	names := []string{action.Owner + "/" + action.Project}
+	if action.Path != "" {
+		names = append(names, names[0]+"/"+action.Path)
+	}
+
+	found := make(map[string]osv.Advisory, 0)
+	for _, name := range names {
+		for _, version := range versions {
+			for _, advisory := range db.Lookup(name, version) {
+				found[advisory.ID] = advisory
+			}
+		}
+	}
+
+	described := make([]string, 0, len(found))
+	for _, id := range slices.Sorted(maps.Keys(found)) {
+		if aliases := found[id].Aliases; len(aliases) > 0 {
+			id = fmt.Sprintf("%s (%s)", id, strings.Join(aliases, ", "))
+		}
+
+		described = append(described, id)
+	}
+
+	return described
+}
+
+// versions returns the semantic versions of the given action. If its ref is not
+// a semantic version, these are the versions of the tags at the same commit for
+// which the commit is recorded in the cache, by entry ID.
+func versions(action *gha.GitHubAction, recorded map[string]github.Revision) []string {
+	if version, ok := osv.Version(action.Ref); ok {
+		return []string{version}
+	}
+
+	id := entryID(action)
+	commit := recorded[strings.Join(id, "@")].Commit
+	if github.IsCommit(action.Ref) {
+		commit = action.Ref
+	}
+
+	found := make([]string, 0)
+	for other, revision := range recorded {
+		name, ref, _ := strings.Cut(other, "@")
+		if commit == "" || revision.Commit != commit || name != id[0] {
+			continue
+		}
+
+		if version, ok := osv.Version(ref); ok {
+			found = append(found, version)
+		}
+	}
+
+	slices.Sort(found)
+	return found
+}
+
+// tagged returns the semantic versions of the tags in the repository of the
+// given action that point to the same commit as the action. The tags of
+// repositories are listed on their remote once, and kept in listed by name.
+func tagged(cfg *Config, action *gha.GitHubAction, recorded map[string]github.Revision, listed map[string]map[string]string) ([]string, error) {
+	id := entryID(action)
+	tags, ok := listed[id[0]]
+	if !ok {
+		var err error
+		if tags, err = remoteTags(cfg, action); err != nil {
+			return nil, err
+		}
+
+		listed[id[0]] = tags
+	}
+
+	commit := recorded[strings.Join(id, "@")].Commit
+	switch {
+	case github.IsCommit(action.Ref):
+		commit = action.Ref
+	case commit == "":
+		commit = tags[action.Ref]
+	}
+
+	found := make([]string, 0)
+	for tag, tagCommit := range tags {
+		if commit == "" || tagCommit != commit {
+			continue
+		}
+
+		if version, ok := osv.Version(tag); ok {
+			found = append(found, version)
+		}
+	}
+
+	slices.Sort(found)
+	return found, nil
+}
+
+// remoteTags returns the commit that every tag in the repository of the given
+// action points to on its remote, by name.
+func remoteTags(cfg *Config, action *gha.GitHubAction) (map[string]string, error) {
+	repo := repository(cfg, action)
+	tags, err := github.Tags(&repo)
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags of %q: %w", entryID(action)[0], err)
+	}
+
+	return tags, nil
+}
+
+func authenticate(fsys fs.FS, sumfile string, raw, signers []byte) Problem {
+	sig, err := fs.ReadFile(fsys, sumfile+signatureExt)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Problem("no signature found")
+	} else if err != nil {
+		return Problem(fmt.Sprintf("could not read signature (%v)", err))
+	}
+
+	if _, err := signature.Verify(raw, sig, signers); err != nil {
+		return Problem(fmt.Sprintf("invalid signature (%v)", err))
+	}
+
+	return ""
+}
 
 func clear(file *os.File) error {
 	if _, err := file.Seek(0, 0); err != nil {
@@ -46,32 +321,181 @@ func clear(file *os.File) error {
 }
 
 func clone(cfg *Config, action *gha.GitHubAction) (string, error) {
-	actionDir := path.Join(cfg.Cache.Path(), action.Owner, action.Project, action.Ref)
-	if _, err := os.Stat(actionDir); err != nil {
-		if cfg.Offline {
-			return actionDir, fmt.Errorf("missing %q from cache", action)
-		}
+	if dir, ok := replacement(cfg, action); ok {
+		return dir, nil
+	}
 
-		repo := github.Repository{
-			Owner:   action.Owner,
-			Project: action.Project,
-			Ref:     action.Ref,
+	repo := repository(cfg, action)
+	actionDir := location(cfg.Cache.Path(), &repo)
+	if !exists(actionDir) && fallback(cfg, action) {
+		connected := connectRepository(cfg, action)
+		if dir := location(cfg.Cache.Path(), &connected); exists(dir) {
+			actionDir = dir
 		}
+	}
 
-		err := github.Clone(actionDir, &repo)
-		if err != nil {
-			return actionDir, fmt.Errorf("clone failed: %v", err)
-		}
+	if exists(actionDir) {
+		slog.Debug("found action in cache", logKeyAction, action.String())
+		return actionDir, nil
+	}
+
+	if cfg.Offline {
+		return actionDir, fmt.Errorf("missing %q from cache", action)
+	}
+
+	slog.Info("fetching action", logKeyAction, action.String())
+	start := time.Now()
+
+	actionDir, revision, err := fetch(cfg, action, cfg.Cache.Path())
+	if err != nil {
+		return actionDir, err
+	}
+
+	slog.Info("fetched action", logKeyAction, action.String(), "commit", revision.Commit, logKeyDuration, time.Since(start))
+
+	if err = recordRevision(cfg, action, revision); err != nil {
+		return actionDir, err
 	}
 
 	return actionDir, nil
 }
 
+// fetch fetches the repository of the given action into its location in the
+// given root directory, from github.com if it does not exist on the GitHub
+// instance (see [fallback]). It returns the directory it was fetched into.
+func fetch(cfg *Config, action *gha.GitHubAction, root string) (string, github.Revision, error) {
+	fetcher := cfg.Fetcher
+	if fetcher == nil {
+		fetcher = &github.GoGit{}
+	}
+
+	repo := repository(cfg, action)
+	dir := location(root, &repo)
+	revision, err := fetcher.Fetch(dir, &repo)
+	if errors.Is(err, github.ErrNotFound) && fallback(cfg, action) {
+		slog.Debug("fetching action from github.com", logKeyAction, action.String(), logKeyErr, err)
+		_ = os.RemoveAll(dir)
+
+		repo = connectRepository(cfg, action)
+		dir = location(root, &repo)
+		revision, err = fetcher.Fetch(dir, &repo)
+	}
+
+	if err != nil {
+		return dir, revision, fmt.Errorf("fetch failed: %w", err)
+	}
+
+	return dir, revision, nil
+}
+
+// location returns the location of the given repository in the given root
+// directory, e.g. the cache.
+func location(root string, repo *github.Repository) string {
+	host := repo.Host
+	if host == github.DefaultHost {
+		host = ""
+	}
+
+	return path.Join(root, host, repo.Owner, repo.Project, repo.Ref)
+}
+
+func exists(dir string) bool {
+	_, err := os.Stat(dir)
+	return err == nil
+}
+
+// revisions returns the revisions that were fetched for the actions in the
+// cache, by entry ID. The revisions are empty if they cannot be read.
+func revisions(cfg *Config) map[string]github.Revision {
+	revisions := make(map[string]github.Revision, 0)
+	if raw, err := os.ReadFile(path.Join(cfg.Cache.Path(), revisionsStore)); err == nil {
+		_ = json.Unmarshal(raw, &revisions)
+	}
+
+	return revisions
+}
+
+// revision returns the revision recorded in the given revisions for the given
+// action. If none was recorded, e.g. because the action was cached before
+// revisions were recorded, it is fetched again to determine the revision unless
+// offline, since the cache does not keep the git metadata of actions. The
+// revision is only used, and recorded, if the files fetched are the same as
+// those in the cache.
+func revision(cfg *Config, action *gha.GitHubAction, recorded map[string]github.Revision) github.Revision {
+	id := strings.Join(entryID(action), "@")
+	if revision, ok := recorded[id]; ok || cfg.Offline {
+		return revision
+	}
+
+	cached, err := clone(cfg, action)
+	if err != nil {
+		return github.Revision{}
+	}
+
+	tmp, err := os.MkdirTemp("", "ghasum-")
+	if err != nil {
+		return github.Revision{}
+	}
+
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	slog.Info("fetching action to determine its revision", logKeyAction, action.String())
+	dir, fetched, err := fetch(cfg, action, tmp)
+	if err != nil {
+		slog.Warn("could not determine the revision of action", logKeyAction, action.String(), logKeyErr, err)
+		return github.Revision{}
+	}
+
+	want, err := checksum.Compute(cached, checksum.BestAlgo)
+	if err != nil {
+		return github.Revision{}
+	}
+
+	if got, err := checksum.Compute(dir, checksum.BestAlgo); err != nil || got != want {
+		slog.Warn("could not determine the revision of action, it changed since it was cached", logKeyAction, action.String())
+		return github.Revision{}
+	}
+
+	recorded[id] = fetched
+	if err := recordRevision(cfg, action, fetched); err != nil {
+		slog.Warn("could not record revision", logKeyAction, action.String(), logKeyErr, err)
+	}
+
+	return fetched
+}
+
+func recordRevision(cfg *Config, action *gha.GitHubAction, revision github.Revision) error {
+	recorded := revisions(cfg)
+
+	id := strings.Join(entryID(action), "@")
+	if revision.Commit == "" {
+		delete(recorded, id)
+	} else {
+		recorded[id] = revision
+	}
+
+	raw, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode revisions: %w", err)
+	}
+
+	file := path.Join(cfg.Cache.Path(), revisionsStore)
+	if err := os.WriteFile(file+".tmp", append(raw, '\n'), 0o600); err != nil {
+		return fmt.Errorf("could not record revision for %q: %w", action, err)
+	}
+
+	if err := os.Rename(file+".tmp", file); err != nil {
+		return fmt.Errorf("could not store revisions: %w", err)
+	}
+
+	return nil
+}
+
 func compare(got, want []sumfile.Entry, reportRedundant bool) []Problem {
 	toMap := func(entries []sumfile.Entry) map[string]string {
 		m := make(map[string]string, len(entries))
 		for _, entry := range entries {
-			key := fmt.Sprintf("%s@%s", entry.ID[0], entry.ID[1])
+			key := strings.Join(entry.ID, "@")
 			m[key] = entry.Checksum
 		}
 
@@ -109,6 +533,52 @@ func compare(got, want []sumfile.Entry, reportRedundant bool) []Problem {
 	return cmp(toMap(got), toMap(want))
 }
 
+func compareGraph(got, want []sumfile.Entry, reportRedundant bool) []Problem {
+	toMap := func(entries []sumfile.Entry) map[string][]string {
+		m := make(map[string][]string, len(entries))
+		for _, entry := range entries {
+			key := strings.Join(entry.ID, "@")
+			if entry.Direct {
+				m[key] = append(m[key], "")
+			}
+
+			for _, parent := range entry.Parents {
+				m[key] = append(m[key], strings.Join(parent, "@"))
+			}
+		}
+
+		return m
+	}
+
+	cmp := func(got, want map[string][]string, format string) []Problem {
+		problems := make([]Problem, 0)
+		for _, key := range slices.Sorted(maps.Keys(got)) {
+			wantParents, ok := want[key]
+			if !ok {
+				continue
+			}
+
+			for _, parent := range got[key] {
+				if !slices.Contains(wantParents, parent) {
+					p := fmt.Sprintf(format, key, usedBy(parent))
+					problems = append(problems, Problem(p))
+				}
+			}
+		}
+
+		return problems
+	}
+
+	gotMap, wantMap := toMap(got), toMap(want)
+
+	problems := cmp(gotMap, wantMap, "no graph edge found for %q used by %s")
+	if reportRedundant {
+		problems = append(problems, cmp(wantMap, gotMap, "redundant graph edge for %q used by %s")...)
+	}
+
+	return problems
+}
+
 func diff(before, after []sumfile.Entry) (uint, uint, uint, uint, uint) {
 	old := make(map[int]sumfile.Entry, len(before))
 	for i, entry := range before {
@@ -158,6 +628,233 @@ func diff(before, after []sumfile.Entry) (uint, uint, uint, uint, uint) {
 	return added, kept, overridden, removed, updated
 }
 
+func entryID(action *gha.GitHubAction) []string {
+	if action.Kind.IsImage() && isDigest(action.Ref) {
+		return []string{gha.DockerPrefix + action.Path, action.Ref}
+	} else if action.Kind.IsImage() {
+		return []string{gha.DockerPrefix + action.Path + ":" + action.Ref}
+	}
+
+	id := fmt.Sprintf("%s/%s", action.Owner, action.Project)
+	if action.Host != "" && action.Host != github.DefaultHost {
+		id = path.Join(action.Host, id)
+	}
+
+	return []string{id, action.Ref}
+}
+
+func fallback(cfg *Config, action *gha.GitHubAction) bool {
+	return cfg.Fallback && action.Host == "" && cfg.Host != "" && cfg.Host != github.DefaultHost
+}
+
+// connectRepository returns the repository of the given action on github.com,
+// where it is fetched from if it is not housed on the GitHub instance (see
+// [fallback]).
+func connectRepository(cfg *Config, action *gha.GitHubAction) github.Repository {
+	return github.Repository{
+		Owner:    action.Owner,
+		Project:  action.Project,
+		Ref:      action.Ref,
+		Rewrites: cfg.Rewrites,
+	}
+}
+
+// lookup looks up the metadata of the repositories of the given actions that
+// are housed on GitHub, using the metadata store in the cache. A failure to
+// update the metadata store is logged, but does not prevent its use.
+func lookup(cfg *Config, actions *tree) map[github.RepoID]github.Record {
+	repos := make([]github.Repository, 0)
+	for action := range actions.All() {
+		if _, ok := replacement(cfg, &action); ok || action.Kind.IsImage() || !onGitHub(cfg, &action) {
+			continue
+		}
+
+		repos = append(repos, repository(cfg, &action))
+	}
+
+	store := github.Store{
+		Path: path.Join(cfg.Cache.Path(), metadataStore),
+		TTL:  cfg.MetadataTTL,
+	}
+
+	metadata, err := store.Lookup(repos, cfg.Offline)
+	if err != nil {
+		slog.Warn("could not store metadata", logKeyErr, err)
+	}
+
+	return metadata
+}
+
+// minAge returns the minimum age for the given action, preferring an override
+// for the exact action over one for its repository over the default.
+func minAge(cfg *Config, action *gha.GitHubAction) time.Duration {
+	id := entryID(action)
+	if d, ok := cfg.MinAges[strings.Join(id, "@")]; ok {
+		return d
+	}
+
+	if d, ok := cfg.MinAges[id[0]]; ok {
+		return d
+	}
+
+	return cfg.MinAge
+}
+
+// moved returns a warning for every repository of the given actions that was
+// renamed or transferred according to the given metadata. The old name of such
+// a repository may be registered again by anyone.
+func moved(cfg *Config, actions *tree, metadata map[github.RepoID]github.Record) []Problem {
+	seen := make(map[github.RepoID]struct{}, 0)
+
+	warnings := make([]Problem, 0)
+	for action := range actions.All() {
+		if _, ok := replacement(cfg, &action); ok || action.Kind.IsImage() {
+			continue
+		}
+
+		repo := repository(cfg, &action)
+		if _, ok := seen[repo.ID()]; ok {
+			continue
+		}
+
+		seen[repo.ID()] = struct{}{}
+		if record, ok := metadata[repo.ID()]; ok && record.RenamedTo != "" {
+			name := entryID(&action)[0]
+			p := fmt.Sprintf("repository %q moved to %q, its old name may be taken over", name, record.RenamedTo)
+			warnings = append(warnings, Problem(p))
+		}
+	}
+
+	slices.Sort(warnings)
+	return warnings
+}
+
+func onGitHub(cfg *Config, action *gha.GitHubAction) bool {
+	return action.Host == "" || action.Host == github.DefaultHost || action.Host == cfg.Host
+}
+
+func replacement(cfg *Config, action *gha.GitHubAction) (string, bool) {
+	if action.Kind.IsImage() {
+		return "", false
+	}
+
+	id := entryID(action)
+	if dir, ok := cfg.Replace[strings.Join(id, "@")]; ok {
+		return dir, true
+	}
+
+	dir, ok := cfg.Replace[id[0]]
+	return dir, ok
+}
+
+func replacements(cfg *Config, actions *tree) map[string]string {
+	replaced := make(map[string]string, 0)
+	for action := range actions.All() {
+		if dir, ok := replacement(cfg, &action); ok {
+			replaced[strings.Join(entryID(&action), "@")] = dir
+		}
+	}
+
+	return replaced
+}
+
+func notReplaced(cfg *Config, actions *tree) error {
+	replaced := replacements(cfg, actions)
+	if len(replaced) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(replaced))
+	for _, id := range slices.Sorted(maps.Keys(replaced)) {
+		ids = append(ids, strconv.Quote(id))
+	}
+
+	return errors.Join(ErrReplaced, fmt.Errorf("replaced %s", strings.Join(ids, ", ")))
+}
+
+// tooNew reports the actions that are younger than their minimum age, based on
+// the revisions recorded when they were fetched (see [revision]). Only actions
+// in ids are checked, or all if ids is nil.
+func tooNew(cfg *Config, actions *tree, ids map[string]struct{}) []Problem {
+	recorded := revisions(cfg)
+	seen := make(map[string]struct{}, 0)
+
+	problems := make([]Problem, 0)
+	for action := range actions.All() {
+		if action.Kind.IsImage() {
+			continue
+		} else if _, ok := replacement(cfg, &action); ok {
+			continue
+		}
+
+		id := strings.Join(entryID(&action), "@")
+		if _, ok := seen[id]; ok {
+			continue
+		} else if _, ok := ids[id]; !ok && ids != nil {
+			continue
+		}
+
+		seen[id] = struct{}{}
+
+		threshold := minAge(cfg, &action)
+		if threshold <= 0 {
+			continue
+		}
+
+		date := revision(cfg, &action, recorded).Date
+		if date.IsZero() {
+			p := fmt.Sprintf("could not determine the age of %q", id)
+			problems = append(problems, Problem(p))
+		} else if time.Since(date) < threshold {
+			p := fmt.Sprintf("%q is younger than the minimum age of %s, released %s", id, days(threshold), age(date))
+			problems = append(problems, Problem(p))
+		}
+	}
+
+	slices.Sort(problems)
+	return problems
+}
+
+func notTooNew(cfg *Config, actions *tree, ids map[string]struct{}) error {
+	problems := tooNew(cfg, actions, ids)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	errs := make([]error, 0, len(problems)+1)
+	errs = append(errs, ErrTooNew)
+	for _, problem := range problems {
+		errs = append(errs, errors.New(string(problem)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// progress reports the progress of finding actions, if it is to be reported.
+func progress(cfg *Config, done, total int) {
+	if cfg.Progress != nil {
+		cfg.Progress(done, total)
+	}
+}
+
+func repository(cfg *Config, action *gha.GitHubAction) github.Repository {
+	repo := github.Repository{
+		Host:     action.Host,
+		Instance: cfg.Host,
+		Owner:    action.Owner,
+		Project:  action.Project,
+		Ref:      action.Ref,
+		Rewrites: cfg.Rewrites,
+	}
+
+	if action.Host == "" || action.Host == cfg.Host {
+		repo.Host = cfg.Host
+		repo.API = cfg.API
+	}
+
+	return repo
+}
+
 func find(cfg *Config) (tree, error) {
 	var (
 		actions []gha.GitHubAction
@@ -175,7 +872,7 @@ func find(cfg *Config) (tree, error) {
 
 	root := tree{}
 	if err != nil {
-		return root, fmt.Errorf("could not find GitHub Actions: %v", err)
+		return root, fmt.Errorf("could not find GitHub Actions: %w", err)
 	}
 
 	parents := make([]*tree, len(actions))
@@ -184,20 +881,31 @@ func find(cfg *Config) (tree, error) {
 	}
 
 	for i := 0; i < len(actions); i++ {
+		progress(cfg, i, len(actions))
+
 		action := actions[i]
 		parent := parents[i]
 
 		project := &action
 		if action.Kind.IsLocal() {
 			project = parent.value
+		} else if action.Kind.IsImage() {
+			project = nil
 		}
 
-		dir := cfg.Path
+		repo := cfg.Repo
 		if project != nil {
-			dir, err = clone(cfg, project)
-			if err != nil {
-				return root, err
+			dir, cloneErr := clone(cfg, project)
+			if cloneErr != nil {
+				return root, cloneErr
+			}
+
+			cloned, openErr := os.OpenRoot(dir)
+			if openErr != nil {
+				return root, fmt.Errorf("could not open repository for %s: %w", project, openErr)
 			}
+
+			repo = cloned.FS()
 		}
 
 		current := parent
@@ -207,20 +915,23 @@ func find(cfg *Config) (tree, error) {
 		}
 
 		if cfg.Transitive || action.Kind.IsLocal() {
-			repo, _ := os.OpenRoot(dir)
-
 			var transitive []gha.GitHubAction
 			switch action.Kind {
 			case gha.Action, gha.LocalAction:
-				transitive, err = gha.ManifestActions(repo.FS(), action.Path)
-				if err != nil {
-					return root, fmt.Errorf("action manifest parsing failed for %s: %v", action, err)
+				transitive, err = gha.ManifestActions(repo, action.Path)
+				if errors.Is(err, gha.ErrInvalidFrom) {
+					// an unsupported Dockerfile should not prevent using the action
+					slog.Warn("could not find base images", logKeyAction, action.String(), logKeyErr, err)
+				} else if err != nil {
+					return root, fmt.Errorf("action manifest parsing failed for %s: %w", action, err)
 				}
 			case gha.ReusableWorkflow, gha.LocalReusableWorkflow:
-				transitive, err = gha.WorkflowActions(repo.FS(), action.Path)
+				transitive, err = gha.WorkflowActions(repo, action.Path)
 				if err != nil {
-					return root, fmt.Errorf("reusable workflow parsing failed for %s: %v", action, err)
+					return root, fmt.Errorf("reusable workflow parsing failed for %s: %w", action, err)
 				}
+			case gha.DockerImage, gha.BaseImage:
+				// Docker images are not parsed for transitive actions
 			}
 
 			for _, action := range transitive {
@@ -230,12 +941,22 @@ func find(cfg *Config) (tree, error) {
 		}
 	}
 
+	progress(cfg, len(actions), len(actions))
+
 	return root, nil
 }
 
+func initCache(c *cache.Cache) error {
+	if err := c.Init(); err != nil {
+		return fmt.Errorf("could not initialize cache: %w", err)
+	}
+
+	return nil
+}
+
 func compute(cfg *Config, actions tree, algo checksum.Algo) ([]sumfile.Entry, error) {
-	if err := cfg.Cache.Init(); err != nil {
-		return nil, fmt.Errorf("could not initialize cache: %v", err)
+	if err := initCache(&cfg.Cache); err != nil {
+		return nil, err
 	} else {
 		defer cfg.Cache.Cleanup()
 	}
@@ -243,30 +964,75 @@ func compute(cfg *Config, actions tree, algo checksum.Algo) ([]sumfile.Entry, er
 	list := slices.Collect(actions.All())
 	entries := make(map[string]sumfile.Entry, len(list))
 	for _, action := range list {
-		actionDir, err := clone(cfg, &action)
-		if err != nil {
-			return nil, err
+		id := strings.Join(entryID(&action), "@")
+		if _, ok := entries[id]; ok || isUnpinned(&action) {
+			continue
 		}
 
-		id := fmt.Sprintf("%s%s%s", action.Owner, action.Project, action.Ref)
-		if _, ok := entries[id]; !ok {
+		var sum, commit string
+		if action.Kind.IsImage() {
+			digest, err := resolve(cfg, &action)
+			if err != nil {
+				return nil, err
+			}
+
+			sum = digest
+		} else if _, ok := replacement(cfg, &action); ok {
+			// replaced actions are not checksummed
+		} else {
+			actionDir, err := clone(cfg, &action)
+			if err != nil {
+				return nil, err
+			}
+
+			start := time.Now()
 			checksum, err := checksum.Compute(actionDir, algo)
 			if err != nil {
-				return nil, fmt.Errorf("could not compute checksum for %q: %v", action, err)
+				return nil, fmt.Errorf("could not compute checksum for %q: %w", action, err)
 			}
 
-			entries[id] = sumfile.Entry{
-				ID:       []string{fmt.Sprintf("%s/%s", action.Owner, action.Project), action.Ref},
-				Checksum: strings.Replace(checksum, "h1:", "", 1),
+			slog.Info("computed checksum", logKeyAction, action.String(), logKeyDuration, time.Since(start))
+
+			sum = strings.Replace(checksum, "h1:", "", 1)
+			commit = revisions(cfg)[id].Commit
+		}
+
+		entries[id] = sumfile.Entry{
+			ID:       entryID(&action),
+			Checksum: sum,
+			Commit:   commit,
+		}
+	}
+
+	for parent, child := range actions.Edges() {
+		if isUnpinned(&child) {
+			continue
+		}
+
+		id := strings.Join(entryID(&child), "@")
+		entry := entries[id]
+
+		if parent == nil {
+			entry.Direct = true
+		} else {
+			parentID := entryID(parent)
+			known := slices.ContainsFunc(entry.Parents, func(id []string) bool {
+				return slices.Equal(id, parentID)
+			})
+
+			if !known && !slices.Equal(parentID, entry.ID) {
+				entry.Parents = append(entry.Parents, parentID)
 			}
 		}
+
+		entries[id] = entry
 	}
 
 	return slices.Collect(maps.Values(entries)), nil
 }
 
-func create(base string) (*os.File, error) {
-	fullGhasumPath := path.Join(base, ghasumPath)
+func create(base, sumfile string) (*os.File, error) {
+	fullGhasumPath := path.Join(base, sumfile)
 
 	if _, err := os.Stat(fullGhasumPath); err == nil {
 		return nil, ErrInitialized
@@ -298,7 +1064,71 @@ func encode(version sumfile.Version, checksums []sumfile.Entry) (string, error)
 	return content, nil
 }
 
-func list(cfg *Config, t *tree) string {
+func gitattributes(base string, patterns []string) error {
+	fullPath := path.Join(base, ".gitattributes")
+
+	existing, err := os.ReadFile(fullPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Join(ErrMergeDriver, err)
+	}
+
+	lines := slices.Collect(strings.Lines(string(existing)))
+
+	var sb strings.Builder
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		sb.WriteRune('\n')
+	}
+
+	for _, pattern := range patterns {
+		line := fmt.Sprintf("%s merge=%s\n", pattern, mergeDriver)
+		if !slices.Contains(lines, line) {
+			sb.WriteString(line)
+		}
+	}
+
+	file, err := os.OpenFile(fullPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.Join(ErrMergeDriver, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if _, err := file.WriteString(sb.String()); err != nil {
+		return errors.Join(ErrMergeDriver, err)
+	}
+
+	return nil
+}
+
+func gitconfig(base string) error {
+	opts := git.PlainOpenOptions{
+		EnableDotGitCommonDir: true,
+	}
+
+	repository, err := git.PlainOpenWithOptions(base, &opts)
+	if err != nil {
+		return errors.Join(ErrMergeDriver, err)
+	}
+
+	cfg, err := repository.Config()
+	if err != nil {
+		return errors.Join(ErrMergeDriver, err)
+	}
+
+	cfg.Raw.Section("merge").Subsection(mergeDriver).
+		SetOption("name", "ghasum checksum file merge driver").
+		SetOption("driver", "ghasum merge %O %A %B")
+
+	if err := repository.SetConfig(cfg); err != nil {
+		return errors.Join(ErrMergeDriver, err)
+	}
+
+	return nil
+}
+
+func list(cfg *Config, t *tree, metadata map[github.RepoID]github.Record, impostors map[string]struct{}) string {
 	var b strings.Builder
 
 	action := t.value
@@ -308,13 +1138,31 @@ func list(cfg *Config, t *tree) string {
 		b.WriteString(action.String())
 		b.WriteString(" (")
 		b.WriteString(action.Kind.String())
-		if !cfg.Offline {
-			isArchived, err := github.Archived(&github.Repository{
-				Owner:   action.Owner,
-				Project: action.Project,
-			})
-			if err == nil && isArchived {
+		if dir, ok := replacement(cfg, action); ok {
+			b.WriteString(", replaced by ")
+			b.WriteString(dir)
+		} else if !action.Kind.IsImage() {
+			repo := repository(cfg, action)
+			record := metadata[repo.ID()]
+
+			var asOf string
+			if cfg.Offline {
+				asOf = " as of " + age(record.Fetched)
+			}
+
+			if record.Archived {
 				b.WriteString(", archived")
+				b.WriteString(asOf)
+			}
+
+			if record.RenamedTo != "" {
+				b.WriteString(", moved to ")
+				b.WriteString(record.RenamedTo)
+				b.WriteString(asOf)
+			}
+
+			if _, ok := impostors[strings.Join(entryID(action), "@")]; ok {
+				b.WriteString(", impostor commit")
 			}
 		}
 		b.WriteString(")\n")
@@ -328,7 +1176,7 @@ func list(cfg *Config, t *tree) string {
 	)
 
 	for _, children := range ordered {
-		for line := range strings.Lines(list(cfg, children)) {
+		for line := range strings.Lines(list(cfg, children, metadata, impostors)) {
 			if !root {
 				b.WriteString("  ")
 			}
@@ -339,8 +1187,67 @@ func list(cfg *Config, t *tree) string {
 	return b.String()
 }
 
-func open(base string) (*os.File, error) {
-	fullGhasumPath := path.Join(base, ghasumPath)
+func listRecorded(entries []sumfile.Entry, parent []string, transitive bool) string {
+	var b strings.Builder
+
+	children := make([]sumfile.Entry, 0)
+	for _, entry := range entries {
+		isChild := entry.Direct
+		if parent != nil {
+			isChild = slices.ContainsFunc(entry.Parents, func(id []string) bool {
+				return slices.Equal(id, parent)
+			})
+		}
+
+		if isChild {
+			children = append(children, entry)
+		}
+	}
+
+	slices.SortFunc(children, func(a, b sumfile.Entry) int {
+		return strings.Compare(strings.Join(a.ID, "@"), strings.Join(b.ID, "@"))
+	})
+
+	for _, child := range children {
+		b.WriteString(strings.Join(child.ID, "@"))
+		b.WriteString("\n")
+
+		if !transitive {
+			continue
+		}
+
+		for line := range strings.Lines(listRecorded(entries, child.ID, transitive)) {
+			b.WriteString("  ")
+			b.WriteString(line)
+		}
+	}
+
+	return b.String()
+}
+
+func markConflicts(conflicts []sumfile.Conflict) string {
+	var sb strings.Builder
+	for _, conflict := range conflicts {
+		id := strings.Join(conflict.ID, "@")
+
+		sb.WriteString("<<<<<<< ours\n")
+		if conflict.Ours != "" {
+			sb.WriteString(id + " " + conflict.Ours + "\n")
+		}
+
+		sb.WriteString("=======\n")
+		if conflict.Theirs != "" {
+			sb.WriteString(id + " " + conflict.Theirs + "\n")
+		}
+
+		sb.WriteString(">>>>>>> theirs\n")
+	}
+
+	return sb.String()
+}
+
+func open(base, sumfile string) (*os.File, error) {
+	fullGhasumPath := path.Join(base, sumfile)
 
 	file, err := os.OpenFile(fullGhasumPath, os.O_RDWR, os.ModeExclusive)
 	if errors.Is(err, fs.ErrNotExist) {
@@ -356,8 +1263,8 @@ func open(base string) (*os.File, error) {
 	return file, nil
 }
 
-func read(fsys fs.FS) ([]byte, error) {
-	file, err := fsys.Open(ghasumPath)
+func read(fsys fs.FS, sumfile string) ([]byte, error) {
+	file, err := fsys.Open(sumfile)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, ErrNotInitialized
 	} else if err != nil {
@@ -376,8 +1283,8 @@ func read(fsys fs.FS) ([]byte, error) {
 	return raw, nil
 }
 
-func remove(base string) error {
-	fullGhasumPath := path.Join(base, ghasumPath)
+func remove(base, sumfile string) error {
+	fullGhasumPath := path.Join(base, sumfile)
 	if err := os.Remove(fullGhasumPath); err != nil {
 		return errors.Join(ErrSumfileRemove, err)
 	}
@@ -385,8 +1292,8 @@ func remove(base string) error {
 	return nil
 }
 
-func unlock(base string) error {
-	fullGhasumPath := path.Join(base, ghasumPath)
+func unlock(base, sumfile string) error {
+	fullGhasumPath := path.Join(base, sumfile)
 	if err := os.Chmod(fullGhasumPath, fs.ModePerm); err != nil {
 		return errors.Join(ErrSumfileUnlock, err)
 	}
@@ -394,6 +1301,270 @@ func unlock(base string) error {
 	return nil
 }
 
+func resolve(cfg *Config, action *gha.GitHubAction) (string, error) {
+	ref, err := oci.ParseReference(strings.TrimPrefix(action.String(), gha.DockerPrefix))
+	if err != nil {
+		return "", fmt.Errorf("could not parse %q: %w", action, err)
+	}
+
+	cached := oci.Layout{Dir: path.Join(cfg.Cache.Path(), imagesDir)}
+	if digest, cacheErr := cached.Resolve(&ref); cacheErr == nil {
+		slog.Debug("found image in cache", logKeyAction, action.String())
+		return digest, nil
+	}
+
+	if cfg.Offline {
+		return "", fmt.Errorf("missing image %q from cache", action)
+	}
+
+	resolver := cfg.Resolver
+	if resolver == nil {
+		resolver = &oci.Registry{}
+	}
+
+	start := time.Now()
+	digest, err := resolver.Resolve(&ref)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %q: %w", action, err)
+	}
+
+	slog.Info("resolved image", logKeyAction, action.String(), "digest", digest, logKeyDuration, time.Since(start))
+
+	if err := cached.Store(&ref, digest); err != nil {
+		return "", fmt.Errorf("could not store %q in the cache: %w", action, err)
+	}
+
+	return digest, nil
+}
+
+func sumfiles(cfg *Config) ([]*Config, error) {
+	if !cfg.SumfilePerWorkflow {
+		single := *cfg
+		if single.Sumfile == "" {
+			single.Sumfile = DefaultSumfile(cfg.Repo)
+		}
+
+		return []*Config{&single}, nil
+	}
+
+	workflows := []string{cfg.Workflow}
+	if cfg.Workflow == "" {
+		var err error
+		workflows, err = gha.Workflows(cfg.Repo)
+		if err != nil {
+			return nil, fmt.Errorf("could not find workflows: %w", err)
+		}
+	}
+
+	cfgs := make([]*Config, len(workflows))
+	for i, workflow := range workflows {
+		perWorkflow := *cfg
+		perWorkflow.Workflow = workflow
+		perWorkflow.Sumfile = workflow + ".sum"
+		cfgs[i] = &perWorkflow
+	}
+
+	return cfgs, nil
+}
+
+// unreachable returns the IDs of the actions pinned to a commit that is not
+// reachable from a branch or tag of their repository, i.e. impostor commits. It
+// also returns a problem for every action for which this could not be checked.
+//
+// Only actions housed on the GitHub instance are checked, and none if offline.
+// Commits found to be reachable are recorded in the cache and not checked again.
+func unreachable(cfg *Config, actions *tree) (map[string]struct{}, []Problem) {
+	impostors := make(map[string]struct{}, 0)
+	problems := make([]Problem, 0)
+	if cfg.Offline {
+		return impostors, problems
+	}
+
+	known := reachableCommits(cfg)
+	stored := len(known)
+	checked := make(map[string]struct{}, 0)
+	for action := range actions.All() {
+		id := strings.Join(entryID(&action), "@")
+		if _, ok := checked[id]; ok || slices.Contains(known, id) {
+			continue
+		}
+
+		checked[id] = struct{}{}
+		if _, ok := replacement(cfg, &action); ok || action.Kind.IsImage() {
+			continue
+		}
+
+		if !github.IsCommit(action.Ref) || !onGitHub(cfg, &action) {
+			continue
+		}
+
+		repo := repository(cfg, &action)
+		reachable, err := github.Reachable(&repo)
+		switch {
+		case err != nil:
+			p := fmt.Sprintf("could not check whether the commit of %q is reachable: %v", id, err)
+			problems = append(problems, Problem(p))
+		case !reachable:
+			impostors[id] = struct{}{}
+		default:
+			known = append(known, id)
+		}
+	}
+
+	if len(known) == stored {
+		return impostors, problems
+	} else if err := recordReachable(cfg, known); err != nil {
+		slog.Warn("could not record reachable commits", logKeyErr, err)
+	}
+
+	return impostors, problems
+}
+
+// reachableCommits returns the IDs of the actions in the cache whose commit was
+// found to be reachable. The IDs are empty if they cannot be read.
+func reachableCommits(cfg *Config) []string {
+	reachable := make([]string, 0)
+	if raw, err := os.ReadFile(path.Join(cfg.Cache.Path(), reachableStore)); err == nil {
+		_ = json.Unmarshal(raw, &reachable)
+	}
+
+	return reachable
+}
+
+func recordReachable(cfg *Config, reachable []string) error {
+	slices.Sort(reachable)
+	raw, err := json.MarshalIndent(slices.Compact(reachable), "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode reachable commits: %w", err)
+	}
+
+	file := path.Join(cfg.Cache.Path(), reachableStore)
+	if err := os.WriteFile(file+".tmp", append(raw, '\n'), 0o600); err != nil {
+		return fmt.Errorf("could not record reachable commits: %w", err)
+	}
+
+	if err := os.Rename(file+".tmp", file); err != nil {
+		return fmt.Errorf("could not store reachable commits: %w", err)
+	}
+
+	return nil
+}
+
+// unsigned reports the actions whose commit and tag are not signed by an allowed
+// signer of their owner, based on the revisions recorded when they were fetched
+// (see [revision]).
+func unsigned(cfg *Config, actions *tree) []Problem {
+	signers := make(map[string][]byte, len(cfg.CommitSigners))
+	for owner, allowed := range cfg.CommitSigners {
+		signers[strings.ToLower(owner)] = allowed
+	}
+
+	recorded := revisions(cfg)
+	seen := make(map[string]struct{}, 0)
+
+	problems := make([]Problem, 0)
+	for action := range actions.All() {
+		if action.Kind.IsImage() {
+			continue
+		} else if _, ok := replacement(cfg, &action); ok {
+			continue
+		}
+
+		name := entryID(&action)[0]
+		id := strings.Join(entryID(&action), "@")
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+
+		owner := path.Dir(name)
+		allowed, ok := signers[strings.ToLower(owner)]
+		if !ok {
+			p := fmt.Sprintf("%q is not signed by an allowed signer, there are none for %q", id, owner)
+			problems = append(problems, Problem(p))
+			continue
+		}
+
+		signed := revision(cfg, &action, recorded).Signed
+		if signed == nil {
+			p := fmt.Sprintf("could not determine whether %q is signed", id)
+			problems = append(problems, Problem(p))
+			continue
+		}
+
+		verified := false
+		reasons := make([]string, 0, len(signed))
+		for _, object := range signed {
+			if _, err := signature.VerifyGit([]byte(object.Payload), object.Signature, allowed); err != nil {
+				reasons = append(reasons, fmt.Sprintf("%s: %v", object.Kind, err))
+			} else {
+				verified = true
+				break
+			}
+		}
+
+		switch {
+		case verified:
+		case len(signed) == 0:
+			p := fmt.Sprintf("%q is not signed", id)
+			problems = append(problems, Problem(p))
+		default:
+			p := fmt.Sprintf("%q is not signed by an allowed signer (%s)", id, strings.Join(reasons, "; "))
+			problems = append(problems, Problem(p))
+		}
+	}
+
+	slices.Sort(problems)
+	return problems
+}
+
+func unpinned(actions *tree) []Problem {
+	seen := make(map[Problem]struct{}, 0)
+
+	problems := make([]Problem, 0)
+	for parent, child := range actions.Edges() {
+		if !isUnpinned(&child) {
+			continue
+		}
+
+		var parentID string
+		if parent != nil {
+			parentID = strings.Join(entryID(parent), "@")
+		}
+
+		id := strings.Join(entryID(&child), "@")
+		p := Problem(fmt.Sprintf("unpinned base image %q used by %s", id, usedBy(parentID)))
+		if _, ok := seen[p]; !ok {
+			seen[p] = struct{}{}
+			problems = append(problems, p)
+		}
+	}
+
+	return problems
+}
+
+// isUnpinned reports whether the given action is a base image identified by a
+// tag. Such images are not checksummed because the tag may be moved upstream at
+// any time, outside the control of the action.
+func isUnpinned(action *gha.GitHubAction) bool {
+	return action.Kind == gha.BaseImage && !isDigest(action.Ref)
+}
+
+// isDigest reports whether the given image ref is a digest rather than a tag.
+func isDigest(ref string) bool {
+	// digests always contain a colon, tags never do
+	return strings.ContainsRune(ref, ':')
+}
+
+func usedBy(parent string) string {
+	if parent == "" {
+		return "the repository"
+	}
+
+	return strconv.Quote(parent)
+}
+
 func version(stored []byte) (sumfile.Version, error) {
 	version, err := sumfile.DecodeVersion(string(stored))
 	if err != nil {
@@ -410,3 +1581,12 @@ func write(file *os.File, content string) error {
 
 	return nil
 }
+
+func writeSignature(base, sumfile string, sig []byte) error {
+	fullSignaturePath := path.Join(base, sumfile+signatureExt)
+	if err := os.WriteFile(fullSignaturePath, sig, 0o644); err != nil {
+		return errors.Join(ErrSignatureWrite, err)
+	}
+
+	return nil
+}
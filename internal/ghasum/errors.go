@@ -21,10 +21,30 @@ var (
 	// initialized but is.
 	ErrInitialized = errors.New("ghasum is already initialized")
 
+	// ErrMergeDriver is the error used when the merge driver could not be
+	// installed.
+	ErrMergeDriver = errors.New("could not install the merge driver")
+
+	// ErrNoGraph is the error used when the ghasum checksum file is expected to
+	// contain a dependency graph but does not.
+	ErrNoGraph = errors.New("the checksum file has no dependency graph")
+
 	// ErrNotInitialized is the error used when ghasum is expected to be
 	// initialized but is not.
 	ErrNotInitialized = errors.New("ghasum has not yet been initialized")
 
+	// ErrReplaced is the error used when checksums would be stored for actions
+	// that are replaced by a local directory.
+	ErrReplaced = errors.New("cannot store checksums for replaced actions")
+
+	// ErrSign is the error used when the ghasum checksum file could not be
+	// signed.
+	ErrSign = errors.New("could not sign the checksum file")
+
+	// ErrSignatureWrite is the error used when the signature for the ghasum
+	// checksum file could not be written.
+	ErrSignatureWrite = errors.New("could not write the signature file")
+
 	// ErrSumfileCreate is the error used when the ghasum checksum file could not
 	// be created.
 	ErrSumfileCreate = errors.New("could not create a checksum file")
@@ -56,4 +76,8 @@ var (
 	// ErrSumfileWrite is the error used when the ghasum checksum file could not
 	// be written to.
 	ErrSumfileWrite = errors.New("could not write to the checksum file")
+
+	// ErrTooNew is the error used when checksums would be stored for actions
+	// that are younger than the minimum age.
+	ErrTooNew = errors.New("cannot store checksums for actions younger than the minimum age")
 )
@@ -15,13 +15,24 @@
 package ghasum
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"maps"
+	"path"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/chains-project/ghasum/internal/cache"
 	"github.com/chains-project/ghasum/internal/checksum"
+	"github.com/chains-project/ghasum/internal/gha"
+	"github.com/chains-project/ghasum/internal/github"
+	"github.com/chains-project/ghasum/internal/oci"
+	"github.com/chains-project/ghasum/internal/osv"
+	"github.com/chains-project/ghasum/internal/signature"
 	"github.com/chains-project/ghasum/internal/sumfile"
 )
 
@@ -39,6 +50,45 @@ type (
 		// for non-read file system operation.
 		Path string
 
+		// Sumfile is the file path (relative to Path) of the checksum file. If
+		// this has the zero value the default checksum file is used.
+		//
+		// Ignored if SumfilePerWorkflow is set.
+		Sumfile string
+
+		// SumfilePerWorkflow sets whether to use a separate checksum file for
+		// every workflow, named after and located next to the workflow.
+		SumfilePerWorkflow bool
+
+		// SumfileRepo is a pointer to the file system hierarchy from which the
+		// checksum file is read. If this has the zero value the checksum file
+		// is read from Repo instead.
+		//
+		// Only applies to verification.
+		SumfileRepo fs.FS
+
+		// Signers is the content of an OpenSSH allowed signers file. If this is
+		// not nil the checksum file must have a valid detached signature made by
+		// one of the allowed signers.
+		//
+		// Only applies to verification.
+		Signers []byte
+
+		// CommitSigners maps owners of actions, identified as in the checksum
+		// file (e.g. "owner" or "host/owner"), to the content of an allowed
+		// signers file with their keys, see [signature.VerifyGit]. If this is
+		// not nil the commit or annotated tag that every action resolves to must
+		// be signed by an allowed signer of its owner.
+		//
+		// Only applies to verification.
+		CommitSigners map[string][]byte
+
+		// Advisories is the content of a zip archive of advisories in the OSV
+		// format, see [osv.Parse].
+		//
+		// Only applies to auditing vulnerabilities.
+		Advisories []byte
+
 		// Workflow is the file path (relative to Path) of the workflow that is
 		// the subject of the operation. If this has the zero value all of the
 		// workflows in the Repo will collectively be the subject of the
@@ -63,6 +113,68 @@ type (
 		// Transitive sets whether to compute/verify checksums for transitive
 		// dependencies.
 		Transitive bool
+
+		// Host is the host of the GitHub instance, e.g. a GitHub Enterprise
+		// Server, that houses actions used without an explicit host. If this
+		// has the zero value github.com is used.
+		Host string
+
+		// API is the base URL of the REST API of the GitHub instance at Host. If
+		// this has the zero value it is derived from Host.
+		API string
+
+		// Fallback sets whether to fetch actions used without an explicit host
+		// from github.com if their repository does not exist on Host, like a
+		// GitHub Enterprise Server does with GitHub Connect.
+		Fallback bool
+
+		// Rewrites are applied to the URLs of the repositories of actions and
+		// of the API, e.g. to use an internal mirror. They do not affect how
+		// actions are identified in the checksum file.
+		Rewrites github.Rewrites
+
+		// MetadataTTL is how long metadata of repositories is kept in the cache
+		// before it is looked up again. If this has the zero value
+		// [github.DefaultTTL] is used.
+		//
+		// Only applies to listing and verifying.
+		MetadataTTL time.Duration
+
+		// MinAge is the minimum age of the commit that an action resolves to,
+		// or of its tag if that was created later. Actions that are younger are
+		// refused. If this has the zero value the age of actions is not checked.
+		//
+		// Only applies to updating and verification.
+		MinAge time.Duration
+
+		// MinAges overrides MinAge for specific actions. Actions are identified
+		// as in Replace. A zero duration disables the check for an action.
+		MinAges map[string]time.Duration
+
+		// Fetcher is used to fetch the repositories of actions. If this has the
+		// zero value repositories are cloned using go-git.
+		Fetcher github.Fetcher
+
+		// Resolver is used to resolve Docker images to the digest of their
+		// manifest. If this has the zero value images are resolved using the
+		// container registry they are located in.
+		Resolver oci.Resolver
+
+		// Progress is called while the actions used by the target are being
+		// found and fetched, with the number of actions processed so far and
+		// the number of actions found so far. If this has the zero value
+		// progress is not reported.
+		Progress func(done, total int)
+
+		// Replace maps actions to a local directory that is used instead of
+		// their repository. Actions are identified as in the checksum file,
+		// either with a ref (e.g. "owner/project@ref") to replace a single
+		// version or without to replace all versions.
+		//
+		// Checksums are not computed for replaced actions, so initialization and
+		// updating fail if an action is replaced and verification does not
+		// verify their checksums.
+		Replace map[string]string
 	}
 
 	// Problem represents an issue detected when verifying ghasum checksums.
@@ -72,56 +184,438 @@ type (
 // Initialize will initialize ghasum for the repository specified in the given
 // configuration.
 func Initialize(cfg *Config) error {
-	file, err := create(cfg.Path)
+	targets, err := sumfiles(cfg)
+	if err != nil {
+		return err
+	}
+
+	for i, target := range targets {
+		if _, err := initialize(target); err != nil {
+			for _, initialized := range targets[:i] {
+				_ = remove(initialized.Path, initialized.Sumfile)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update will update the ghasum checksums for the repository specified in the
+// given configuration.
+func Update(cfg *Config, force bool) (UpdateReport, error) {
+	var report UpdateReport
+
+	targets, err := sumfiles(cfg)
+	if err != nil {
+		return report, err
+	}
+
+	missing := make([]*Config, 0)
+	for _, target := range targets {
+		updated, err := update(target, force)
+		if errors.Is(err, ErrNotInitialized) {
+			missing = append(missing, target)
+			continue
+		} else if err != nil {
+			return report, err
+		}
+
+		report.Added += updated.Added
+		report.Kept += updated.Kept
+		report.Overridden += updated.Overridden
+		report.Removed += updated.Removed
+		report.Updated += updated.Updated
+	}
+
+	if len(missing) == len(targets) {
+		return report, ErrNotInitialized
+	}
+
+	for _, target := range missing {
+		checksums, err := initialize(target)
+		if err != nil {
+			return report, err
+		}
+
+		report.Added += uint(len(checksums))
+	}
+
+	return report, nil
+}
+
+// Verify will compare the stored ghasum checksums against recomputed checksums
+// for the repository specified in the given configuration.
+//
+// Verification report checksums that do not match and checksums that are
+// missing. It does not report checksums that are not used. Base images that are
+// not pinned by digest and replaced actions are reported as warnings.
+func Verify(cfg *Config) (VerifyReport, error) {
+	var report VerifyReport
+
+	targets, err := sumfiles(cfg)
+	if err != nil {
+		return report, err
+	}
+
+	reportRedundant := cfg.Job == "" && (cfg.Workflow == "" || cfg.SumfilePerWorkflow)
+
+	missing := make([]*Config, 0)
+	for _, target := range targets {
+		verified, err := verify(target, reportRedundant)
+		if errors.Is(err, ErrNotInitialized) {
+			missing = append(missing, target)
+			continue
+		} else if err != nil {
+			return report, err
+		}
+
+		if cfg.SumfilePerWorkflow {
+			for i, problem := range verified.Problems {
+				verified.Problems[i] = Problem(fmt.Sprintf("%s in %q", problem, target.Sumfile))
+			}
+		}
+
+		report.Problems = append(report.Problems, verified.Problems...)
+		for _, warning := range verified.Warnings {
+			if !slices.Contains(report.Warnings, warning) {
+				report.Warnings = append(report.Warnings, warning)
+			}
+		}
+
+		report.Total += verified.Total
+	}
+
+	if len(missing) == len(targets) {
+		return report, ErrNotInitialized
+	}
+
+	for _, target := range missing {
+		p := fmt.Sprintf("no checksum file found for %q", target.Workflow)
+		report.Problems = append(report.Problems, Problem(p))
+	}
+
+	return report, nil
+}
+
+// Merge will merge the checksum files ours and theirs, which were both derived
+// from the checksum file base, into a new checksum file.
+//
+// Checksums that were added, changed, or removed on only one side are merged
+// automatically. Checksums that were changed differently on both sides are
+// reported as conflicts and marked as such in the new checksum file.
+func Merge(base, ours, theirs []byte) (string, MergeReport, error) {
+	var report MergeReport
+
+	baseChecksums := make([]sumfile.Entry, 0)
+	if len(bytes.TrimSpace(base)) > 0 {
+		var err error
+		baseChecksums, err = decode(base)
+		if err != nil {
+			return "", report, err
+		}
+	}
+
+	oursVersion, err := version(ours)
+	if err != nil {
+		return "", report, err
+	}
+
+	oursChecksums, err := decode(ours)
+	if err != nil {
+		return "", report, err
+	}
+
+	theirsVersion, err := version(theirs)
+	if err != nil {
+		return "", report, err
+	}
+
+	theirsChecksums, err := decode(theirs)
+	if err != nil {
+		return "", report, err
+	}
+
+	checksums, conflicts, graphErr := sumfile.Merge(baseChecksums, oursChecksums, theirsChecksums)
+	content, err := encode(max(oursVersion, theirsVersion), checksums)
+	if err != nil {
+		return "", report, err
+	}
+
+	for _, conflict := range conflicts {
+		p := fmt.Sprintf("conflicting checksums for %q", strings.Join(conflict.ID, "@"))
+		report.Conflicts = append(report.Conflicts, Problem(p))
+	}
+
+	if graphErr != nil {
+		reason := strings.ReplaceAll(graphErr.Error(), "\n", ": ")
+		p := Problem("conflicting dependency graphs, the graph was omitted: " + reason)
+		report.Conflicts = append(report.Conflicts, p)
+	}
+
+	content += markConflicts(conflicts)
+	return content, report, nil
+}
+
+// DefaultSumfile returns the path to the checksum file used for the repository
+// at the given file system hierarchy if no path is configured explicitly.
+func DefaultSumfile(repo fs.FS) string {
+	return path.Join(gha.WorkflowsDir(repo), "gha.sum")
+}
+
+// InstallMergeDriver will configure [Merge] as the git merge driver for the
+// checksum file(s) of the repository specified in the given configuration.
+func InstallMergeDriver(cfg *Config) error {
+	patterns := []string{"/" + DefaultSumfile(cfg.Repo)}
+	switch {
+	case cfg.SumfilePerWorkflow:
+		dir := gha.WorkflowsDir(cfg.Repo)
+		patterns = []string{
+			"/" + path.Join(dir, "*.yml.sum"),
+			"/" + path.Join(dir, "*.yaml.sum"),
+		}
+	case cfg.Sumfile != "":
+		patterns = []string{"/" + cfg.Sumfile}
+	}
+
+	if err := gitconfig(cfg.Path); err != nil {
+		return err
+	}
+
+	if err := gitattributes(cfg.Path, patterns); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Sign will create a detached signature for the checksum file(s) of the
+// repository specified in the given configuration using the given private SSH
+// key.
+func Sign(cfg *Config, key []byte) error {
+	targets, err := sumfiles(cfg)
 	if err != nil {
 		return err
 	}
 
+	signed := 0
+	for _, target := range targets {
+		raw, err := read(target.Repo, target.Sumfile)
+		if errors.Is(err, ErrNotInitialized) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if _, err = decode(raw); err != nil {
+			return err
+		}
+
+		sig, err := signature.Sign(raw, key)
+		if err != nil {
+			return errors.Join(ErrSign, err)
+		}
+
+		if err := writeSignature(target.Path, target.Sumfile, sig); err != nil {
+			return err
+		}
+
+		signed++
+	}
+
+	if signed == 0 {
+		return ErrNotInitialized
+	}
+
+	return nil
+}
+
+// List will compute and return the list of GitHub Actions dependencies for the
+// repository specified in the given configuration.
+func List(cfg *Config) (string, error) {
+	if err := initCache(&cfg.Cache); err != nil {
+		return "", err
+	} else {
+		defer cfg.Cache.Cleanup()
+	}
+
+	actions, err := find(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	metadata := lookup(cfg, &actions)
+
+	impostors, _ := unreachable(cfg, &actions)
+	return list(cfg, &actions, metadata, impostors), nil
+}
+
+// RefreshMetadata will look up the metadata of all repositories in the metadata
+// store of the given cache again, returning the number of repositories that were
+// refreshed.
+func RefreshMetadata(c *cache.Cache) (int, error) {
+	store := github.Store{Path: path.Join(c.Path(), metadataStore)}
+	refreshed, err := store.Refresh()
+	if err != nil {
+		return 0, fmt.Errorf("could not refresh metadata: %w", err)
+	}
+
+	return refreshed, nil
+}
+
+// ListRecorded will return the list of GitHub Actions dependencies recorded in
+// the checksum file of the repository specified in the given configuration.
+//
+// This requires the checksum file to contain a dependency graph. It does not
+// use the cache nor fetch any repositories.
+func ListRecorded(cfg *Config) (string, error) {
+	targets, err := sumfiles(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, target := range targets {
+		raw, err := read(target.Repo, target.Sumfile)
+		if err != nil {
+			return "", err
+		}
+
+		entries, err := decode(raw)
+		if err != nil {
+			return "", err
+		}
+
+		if len(entries) > 0 && !sumfile.HasGraph(entries) {
+			return "", ErrNoGraph
+		}
+
+		b.WriteString(listRecorded(entries, nil, cfg.Transitive))
+	}
+
+	return b.String(), nil
+}
+
+// AuditTags will check whether the tags used by the actions recorded in the
+// checksum file of the repository specified in the given configuration still
+// point to the commit for which the checksum was computed.
+//
+// This requires the checksum file to record commits. It does not use the cache
+// nor fetch any repositories, only the tags of repositories are listed.
+func AuditTags(cfg *Config) (AuditReport, error) {
+	var report AuditReport
+
+	targets, err := sumfiles(cfg)
+	if err != nil {
+		return report, err
+	}
+
+	seen := make(map[string]struct{}, 0)
+	entries := make([]sumfile.Entry, 0)
+	for _, target := range targets {
+		raw, err := read(target.Repo, target.Sumfile)
+		if err != nil {
+			return report, err
+		}
+
+		stored, err := decode(raw)
+		if err != nil {
+			return report, err
+		}
+
+		for _, entry := range stored {
+			id := strings.Join(entry.ID, "@")
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return auditTags(cfg, entries)
+}
+
+// AuditVulns will match all actions in the repository specified in the given
+// configuration, including transitive actions, against the advisories in the
+// OSV database from the configuration.
+func AuditVulns(cfg *Config) (AuditReport, error) {
+	if err := initCache(&cfg.Cache); err != nil {
+		return AuditReport{}, err
+	} else {
+		defer cfg.Cache.Cleanup()
+	}
+
+	db, err := osv.Parse(cfg.Advisories)
+	if err != nil {
+		return AuditReport{}, fmt.Errorf("could not read advisories: %w", err)
+	}
+
+	actions, err := find(cfg)
+	if err != nil {
+		return AuditReport{}, err
+	}
+
+	return auditVulns(cfg, &actions, db), nil
+}
+
+func initialize(cfg *Config) ([]sumfile.Entry, error) {
+	file, err := create(cfg.Path, cfg.Sumfile)
+	if err != nil {
+		return nil, err
+	}
+
 	defer func() {
 		deinitialize := (err != nil)
 		if err = file.Close(); err != nil || deinitialize {
-			_ = remove(cfg.Path)
+			_ = remove(cfg.Path, cfg.Sumfile)
 		}
 	}()
 
 	actions, err := find(cfg)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err = notReplaced(cfg, &actions); err != nil {
+		return nil, err
 	}
 
 	checksums, err := compute(cfg, actions, checksum.BestAlgo)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err = notTooNew(cfg, &actions, nil); err != nil {
+		return nil, err
 	}
 
 	content, err := encode(sumfile.VersionLatest, checksums)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := write(file, content); err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := unlock(cfg.Path); err != nil {
-		return err
+	if err := unlock(cfg.Path, cfg.Sumfile); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return checksums, nil
 }
 
-// Update will update the ghasum checksums for the repository specified in the
-// given configuration.
-func Update(cfg *Config, force bool) (UpdateReport, error) {
+func update(cfg *Config, force bool) (UpdateReport, error) {
 	var report UpdateReport
 
-	file, err := open(cfg.Path)
+	file, err := open(cfg.Path, cfg.Sumfile)
 	if err != nil {
 		return report, err
 	}
 
 	defer func() {
-		_ = unlock(cfg.Path)
+		_ = unlock(cfg.Path, cfg.Sumfile)
 		_ = file.Close()
 	}()
 
@@ -147,6 +641,10 @@ func Update(cfg *Config, force bool) (UpdateReport, error) {
 		return report, err
 	}
 
+	if err = notReplaced(cfg, &actions); err != nil {
+		return report, err
+	}
+
 	checksums, err := compute(cfg, actions, checksum.BestAlgo)
 	if err != nil {
 		return report, err
@@ -156,13 +654,29 @@ func Update(cfg *Config, force bool) (UpdateReport, error) {
 		for i, entry := range checksums {
 			for _, oldEntry := range oldChecksums {
 				if slices.Equal(entry.ID, oldEntry.ID) {
-					checksums[i] = oldEntry
+					checksums[i].Checksum = oldEntry.Checksum
+					checksums[i].Commit = oldEntry.Commit
 					break
 				}
 			}
 		}
 	}
 
+	adopted := make(map[string]struct{}, 0)
+	for _, entry := range checksums {
+		known := slices.ContainsFunc(oldChecksums, func(oldEntry sumfile.Entry) bool {
+			return slices.Equal(entry.ID, oldEntry.ID) && entry.Checksum == oldEntry.Checksum
+		})
+
+		if !known {
+			adopted[strings.Join(entry.ID, "@")] = struct{}{}
+		}
+	}
+
+	if err = notTooNew(cfg, &actions, adopted); err != nil {
+		return report, err
+	}
+
 	encoded, err := encode(version, checksums)
 	if err != nil {
 		return report, err
@@ -176,7 +690,7 @@ func Update(cfg *Config, force bool) (UpdateReport, error) {
 		return report, err
 	}
 
-	if err := unlock(cfg.Path); err != nil {
+	if err := unlock(cfg.Path, cfg.Sumfile); err != nil {
 		return report, err
 	}
 
@@ -190,19 +704,26 @@ func Update(cfg *Config, force bool) (UpdateReport, error) {
 	return report, nil
 }
 
-// Verify will compare the stored ghasum checksums against recomputed checksums
-// for the repository specified in the given configuration.
-//
-// Verification report checksums that do not match and checksums that are
-// missing. It does not report checksums that are not used.
-func Verify(cfg *Config) (VerifyReport, error) {
+func verify(cfg *Config, reportRedundant bool) (VerifyReport, error) {
 	var report VerifyReport
 
-	raw, err := read(cfg.Repo)
+	sumfileRepo := cfg.Repo
+	if cfg.SumfileRepo != nil {
+		sumfileRepo = cfg.SumfileRepo
+	}
+
+	raw, err := read(sumfileRepo, cfg.Sumfile)
 	if err != nil {
 		return report, err
 	}
 
+	if cfg.Signers != nil {
+		if problem := authenticate(sumfileRepo, cfg.Sumfile, raw, cfg.Signers); problem != "" {
+			report.Problems = []Problem{problem}
+			return report, nil
+		}
+	}
+
 	stored, err := decode(raw)
 	if err != nil {
 		return report, err
@@ -218,20 +739,40 @@ func Verify(cfg *Config) (VerifyReport, error) {
 		return report, err
 	}
 
-	reportRedundant := cfg.Workflow == "" && cfg.Job == ""
-	report.Problems = compare(fresh, stored, reportRedundant)
-	report.Total = len(fresh)
+	replaced := replacements(cfg, &actions)
+	isReplaced := func(entry sumfile.Entry) bool {
+		_, ok := replaced[strings.Join(entry.ID, "@")]
+		return ok
+	}
 
-	return report, nil
-}
+	verifiable := slices.DeleteFunc(slices.Clone(fresh), isReplaced)
+	report.Problems = compare(verifiable, slices.DeleteFunc(slices.Clone(stored), isReplaced), reportRedundant)
+	if sumfile.HasGraph(stored) {
+		report.Problems = append(report.Problems, compareGraph(fresh, stored, reportRedundant)...)
+	}
 
-// List will compute and return the list of GitHub Actions dependencies for the
-// repository specified in the given configuration.
-func List(cfg *Config) (string, error) {
-	actions, err := find(cfg)
-	if err != nil {
-		return "", err
+	impostors, undetermined := unreachable(cfg, &actions)
+	for _, id := range slices.Sorted(maps.Keys(impostors)) {
+		p := fmt.Sprintf("impostor commit for %q, not reachable from a branch or tag of the repository", id)
+		report.Problems = append(report.Problems, Problem(p))
 	}
 
-	return list(cfg, &actions), nil
+	report.Problems = append(report.Problems, undetermined...)
+
+	metadata := lookup(cfg, &actions)
+
+	report.Problems = append(report.Problems, tooNew(cfg, &actions, nil)...)
+	if cfg.CommitSigners != nil {
+		report.Problems = append(report.Problems, unsigned(cfg, &actions)...)
+	}
+
+	report.Warnings = append(unpinned(&actions), moved(cfg, &actions, metadata)...)
+	for _, id := range slices.Sorted(maps.Keys(replaced)) {
+		p := fmt.Sprintf("checksum not verified for %q, replaced by %q", id, replaced[id])
+		report.Warnings = append(report.Warnings, Problem(p))
+	}
+
+	report.Total = len(verifiable)
+
+	return report, nil
 }
@@ -0,0 +1,86 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghasum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chains-project/ghasum/internal/cache"
+	"github.com/chains-project/ghasum/internal/github"
+)
+
+// failingFetcher is a [github.Fetcher] that always fails with err.
+type failingFetcher struct {
+	err error
+}
+
+func (f *failingFetcher) Fetch(string, *github.Repository) (github.Revision, error) {
+	return github.Revision{}, f.err
+}
+
+func TestVerifyFetchError(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]error{
+		"canceled": context.Canceled,
+		"timeout":  context.DeadlineExceeded,
+	}
+
+	for name, want := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := t.TempDir()
+			files := map[string]string{
+				".github/workflows/gha.sum":      "version 3\n\nactions/checkout@v4 +34igsJdK09ZFEkVNQ+ZoyZnIlg48X3bm4ZaGGlX5o8=\n",
+				".github/workflows/workflow.yml": "on: [push]\njobs:\n  example:\n    runs-on: ubuntu-latest\n    steps:\n    - uses: actions/checkout@v4\n",
+			}
+
+			for name, content := range files {
+				file := filepath.Join(repo, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+					t.Fatalf("Could not create directory for %q: %v", name, err)
+				}
+
+				if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+					t.Fatalf("Could not write %q: %v", name, err)
+				}
+			}
+
+			c, err := cache.New(cache.WithLocation(t.TempDir()), cache.WithEviction(false))
+			if err != nil {
+				t.Fatalf("Could not create cache: %v", err)
+			}
+
+			cfg := Config{
+				Repo:       os.DirFS(repo),
+				Path:       repo,
+				Cache:      c,
+				Transitive: true,
+				Fetcher:    &failingFetcher{err: fmt.Errorf("request failed: %w", want)},
+			}
+
+			_, err = Verify(&cfg)
+			if !errors.Is(err, want) {
+				t.Errorf("Incorrect error (got %v, want %v)", err, want)
+			}
+		})
+	}
+}
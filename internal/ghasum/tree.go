@@ -16,6 +16,7 @@ package ghasum
 
 import (
 	"iter"
+	"slices"
 
 	"github.com/chains-project/ghasum/internal/gha"
 )
@@ -48,3 +49,36 @@ func (t *tree) every(f func(gha.GitHubAction) bool) bool {
 
 	return true
 }
+
+func (t *tree) Paths() iter.Seq[[]gha.GitHubAction] {
+	return func(yield func([]gha.GitHubAction) bool) {
+		_ = t.everyPath(nil, yield)
+	}
+}
+
+func (t *tree) everyPath(parents []gha.GitHubAction, f func([]gha.GitHubAction) bool) bool {
+	for _, child := range t.children {
+		path := append(slices.Clone(parents), *child.value)
+		if !f(path) || !child.everyPath(path, f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *tree) Edges() iter.Seq2[*gha.GitHubAction, gha.GitHubAction] {
+	return func(yield func(*gha.GitHubAction, gha.GitHubAction) bool) {
+		_ = t.everyEdge(yield)
+	}
+}
+
+func (t *tree) everyEdge(f func(*gha.GitHubAction, gha.GitHubAction) bool) bool {
+	for _, child := range t.children {
+		if !f(t.value, *child.value) || !child.everyEdge(f) {
+			return false
+		}
+	}
+
+	return true
+}
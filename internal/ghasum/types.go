@@ -14,6 +14,24 @@
 
 package ghasum
 
+// AuditReport is a report produced by [AuditTags].
+type AuditReport struct {
+	// The list of problems that were found during the audit.
+	Problems []Problem
+
+	// The total number of tags that were audited.
+	Total int
+
+	// The list of warnings that occurred during the audit.
+	Warnings []Problem
+}
+
+// MergeReport is a report produced by [Merge].
+type MergeReport struct {
+	// The list of conflicts that occurred during merging.
+	Conflicts []Problem
+}
+
 // UpdateReport is a report produced by [Update].
 type UpdateReport struct {
 	// The number of checksums that were added.
@@ -39,4 +57,7 @@ type VerifyReport struct {
 
 	// The total number of actions that were verified.
 	Total int
+
+	// The list of warnings that occurred during verification.
+	Warnings []Problem
 }
@@ -0,0 +1,393 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitfs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type (
+	treeFS struct {
+		tree    *object.Tree
+		modTime time.Time
+	}
+
+	file struct {
+		io.ReadCloser
+		info fileInfo
+	}
+
+	dir struct {
+		info    fileInfo
+		entries []fs.DirEntry
+		offset  int
+	}
+
+	fileInfo struct {
+		name    string
+		size    int64
+		mode    fs.FileMode
+		modTime time.Time
+	}
+)
+
+const (
+	opOpen     = "open"
+	opReadLink = "readlink"
+	opLstat    = "lstat"
+
+	// maxLinks is the maximum number of symbolic links followed when resolving
+	// a path, matching the limit used by Linux.
+	maxLinks = 40
+)
+
+// ErrRevisionNotFound is the error returned by [Open] if the revision does not
+// exist or is not valid.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+var (
+	errEscape   = errors.New("path escapes from the repository")
+	errLinkLoop = errors.New("too many levels of symbolic links")
+)
+
+// Open returns a read-only file system for the tree of the given revision in
+// the git repository at the given path. The revision can be anything git can
+// resolve to a commit, such as a branch, tag, or commit SHA.
+//
+// Symbolic links in the tree are followed as long as they stay within the
+// tree. The file system implements [fs.ReadLinkFS] to inspect links without
+// following them.
+func Open(repo, rev string) (fs.FS, error) {
+	opts := git.PlainOpenOptions{
+		EnableDotGitCommonDir: true,
+	}
+
+	repository, err := git.PlainOpenWithOptions(repo, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not open git repository at %q: %w", repo, err)
+	}
+
+	hash, err := repository.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve revision %q: %w (%w)", rev, ErrRevisionNotFound, err)
+	}
+
+	commit, err := repository.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("could not get commit for revision %q: %w", rev, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get tree for revision %q: %w", rev, err)
+	}
+
+	fsys := &treeFS{
+		tree:    tree,
+		modTime: commit.Committer.When,
+	}
+
+	return fsys, nil
+}
+
+func (f *treeFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: opOpen, Path: name, Err: fs.ErrInvalid}
+	}
+
+	resolved, entry, err := f.resolve(name, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: opOpen, Path: name, Err: err}
+	}
+
+	if entry == nil {
+		return f.openDir(name, f.tree)
+	}
+
+	switch {
+	case entry.Mode == filemode.Dir:
+		tree, err := f.tree.Tree(resolved)
+		if err != nil {
+			return nil, &fs.PathError{Op: opOpen, Path: name, Err: err}
+		}
+
+		return f.openDir(name, tree)
+	case entry.Mode.IsFile():
+		blob, err := f.tree.TreeEntryFile(entry)
+		if err != nil {
+			return nil, &fs.PathError{Op: opOpen, Path: name, Err: err}
+		}
+
+		reader, err := blob.Reader()
+		if err != nil {
+			return nil, &fs.PathError{Op: opOpen, Path: name, Err: err}
+		}
+
+		file := &file{
+			ReadCloser: reader,
+			info:       f.fileInfo(path.Base(name), entry.Mode, blob.Size),
+		}
+
+		return file, nil
+	default:
+		return nil, &fs.PathError{Op: opOpen, Path: name, Err: fs.ErrNotExist}
+	}
+}
+
+func (f *treeFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: opReadLink, Path: name, Err: fs.ErrInvalid}
+	}
+
+	_, entry, err := f.resolve(name, false)
+	if err != nil {
+		return "", &fs.PathError{Op: opReadLink, Path: name, Err: err}
+	}
+
+	if entry == nil || entry.Mode != filemode.Symlink {
+		return "", &fs.PathError{Op: opReadLink, Path: name, Err: fs.ErrInvalid}
+	}
+
+	target, err := f.readLink(entry)
+	if err != nil {
+		return "", &fs.PathError{Op: opReadLink, Path: name, Err: err}
+	}
+
+	return target, nil
+}
+
+func (f *treeFS) Lstat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: opLstat, Path: name, Err: fs.ErrInvalid}
+	}
+
+	_, entry, err := f.resolve(name, false)
+	if err != nil {
+		return nil, &fs.PathError{Op: opLstat, Path: name, Err: err}
+	}
+
+	if entry == nil {
+		return f.fileInfo(path.Base(name), filemode.Dir, 0), nil
+	}
+
+	var size int64
+	switch {
+	case entry.Mode == filemode.Dir:
+	case entry.Mode.IsFile():
+		blob, err := f.tree.TreeEntryFile(entry)
+		if err != nil {
+			return nil, &fs.PathError{Op: opLstat, Path: name, Err: err}
+		}
+
+		size = blob.Size
+	default:
+		return nil, &fs.PathError{Op: opLstat, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return f.fileInfo(path.Base(name), entry.Mode, size), nil
+}
+
+// resolve looks up name in the tree, following symbolic links in all but the
+// last element of name and, if follow is set, in the last element as well. It
+// returns the resolved path and its entry, which is nil for the root of the
+// tree. Links pointing outside the tree are rejected.
+func (f *treeFS) resolve(name string, follow bool) (string, *object.TreeEntry, error) {
+	var (
+		entry    *object.TreeEntry
+		resolved = "."
+		links    = 0
+		rest     = split(name)
+	)
+
+	for len(rest) > 0 {
+		current := path.Join(resolved, rest[0])
+		rest = rest[1:]
+
+		var err error
+		entry, err = f.tree.FindEntry(current)
+		if err != nil {
+			return "", nil, fs.ErrNotExist
+		}
+
+		if entry.Mode != filemode.Symlink || (len(rest) == 0 && !follow) {
+			resolved = current
+			continue
+		}
+
+		if links++; links > maxLinks {
+			return "", nil, errLinkLoop
+		}
+
+		target, err := f.readLink(entry)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if path.IsAbs(target) {
+			return "", nil, errEscape
+		}
+
+		target = path.Join(path.Dir(current), target)
+		if !fs.ValidPath(target) {
+			return "", nil, errEscape
+		}
+
+		entry, resolved, rest = nil, ".", append(split(target), rest...)
+	}
+
+	return resolved, entry, nil
+}
+
+// readLink returns the target of the symbolic link entry.
+func (f *treeFS) readLink(entry *object.TreeEntry) (string, error) {
+	blob, err := f.tree.TreeEntryFile(entry)
+	if err != nil {
+		return "", fmt.Errorf("could not find link target: %w", err)
+	}
+
+	target, err := blob.Contents()
+	if err != nil {
+		return "", fmt.Errorf("could not read link target: %w", err)
+	}
+
+	return target, nil
+}
+
+func (f *treeFS) openDir(name string, tree *object.Tree) (*dir, error) {
+	entries := make([]fs.DirEntry, 0, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		var size int64
+		switch {
+		case entry.Mode == filemode.Dir:
+		case entry.Mode.IsFile():
+			blob, err := tree.TreeEntryFile(&entry)
+			if err != nil {
+				return nil, &fs.PathError{Op: opOpen, Path: name, Err: err}
+			}
+
+			size = blob.Size
+		default:
+			continue
+		}
+
+		info := f.fileInfo(entry.Name, entry.Mode, size)
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	dir := &dir{
+		info:    f.fileInfo(path.Base(name), filemode.Dir, 0),
+		entries: entries,
+	}
+
+	return dir, nil
+}
+
+func (f *treeFS) fileInfo(name string, mode filemode.FileMode, size int64) fileInfo {
+	var fsMode fs.FileMode
+	switch {
+	case mode == filemode.Dir:
+		fsMode = fs.ModeDir | 0o555
+	case mode == filemode.Executable:
+		fsMode = 0o555
+	case mode == filemode.Symlink:
+		fsMode = fs.ModeSymlink | 0o444
+	default:
+		fsMode = 0o444
+	}
+
+	return fileInfo{
+		name:    name,
+		size:    size,
+		mode:    fsMode,
+		modTime: f.modTime,
+	}
+}
+
+// split returns the elements of the valid path name, which are none for the
+// root.
+func split(name string) []string {
+	if name == "." {
+		return nil
+	}
+
+	return strings.Split(name, "/")
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (d *dir) Close() error {
+	return nil
+}
+
+func (d *dir) Read(_ []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (i fileInfo) IsDir() bool {
+	return i.mode.IsDir()
+}
+
+func (i fileInfo) ModTime() time.Time {
+	return i.modTime
+}
+
+func (i fileInfo) Mode() fs.FileMode {
+	return i.mode
+}
+
+func (i fileInfo) Name() string {
+	return i.name
+}
+
+func (i fileInfo) Size() int64 {
+	return i.size
+}
+
+func (i fileInfo) Sys() any {
+	return nil
+}
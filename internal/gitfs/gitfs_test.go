@@ -0,0 +1,300 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+	repository, err := git.PlainInit(repo, false)
+	if err != nil {
+		t.Fatalf("Could not initialize repository: %v", err)
+	}
+
+	mustCommit(t, repository, repo, map[string]string{
+		".github/workflows/workflow.yml": "version: 1\n",
+		"README.md":                      "Hello world!\n",
+	})
+
+	if _, err := repository.CreateTag("v1", mustHead(t, repository), nil); err != nil {
+		t.Fatalf("Could not create tag: %v", err)
+	}
+
+	mustCommit(t, repository, repo, map[string]string{
+		".github/workflows/workflow.yml": "version: 2\n",
+		"nested/dir/file.txt":            "foobar\n",
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		t.Parallel()
+
+		fsys, err := Open(repo, "HEAD")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		err = fstest.TestFS(fsys, ".github/workflows/workflow.yml", "README.md", "nested/dir/file.txt")
+		if err != nil {
+			t.Fatalf("Unexpected file system: %v", err)
+		}
+
+		got, _ := fs.ReadFile(fsys, ".github/workflows/workflow.yml")
+		if want := "version: 2\n"; string(got) != want {
+			t.Errorf("Unexpected content (got %q, want %q)", got, want)
+		}
+	})
+
+	t.Run("Previous", func(t *testing.T) {
+		t.Parallel()
+
+		fsys, err := Open(repo, "v1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		got, _ := fs.ReadFile(fsys, ".github/workflows/workflow.yml")
+		if want := "version: 1\n"; string(got) != want {
+			t.Errorf("Unexpected content (got %q, want %q)", got, want)
+		}
+
+		if _, err := fsys.Open("nested/dir/file.txt"); err == nil {
+			t.Error("Expected file to not exist")
+		}
+	})
+
+	t.Run("Unknown revision", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Open(repo, "this-is-not-a-revision"); !errors.Is(err, ErrRevisionNotFound) {
+			t.Errorf("Unexpected error (got %v, want %v)", err, ErrRevisionNotFound)
+		}
+	})
+
+	t.Run("Invalid revision", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Open(repo, "HEAD^{/"); !errors.Is(err, ErrRevisionNotFound) {
+			t.Errorf("Unexpected error (got %v, want %v)", err, ErrRevisionNotFound)
+		}
+	})
+
+	t.Run("Not a repository", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Open(t.TempDir(), "HEAD"); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestSymlinks(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+	repository, err := git.PlainInit(repo, false)
+	if err != nil {
+		t.Fatalf("Could not initialize repository: %v", err)
+	}
+
+	links := map[string]string{
+		"link-file":       "README.md",
+		"link-dir":        "nested",
+		"link-link":       "link-file",
+		"nested/up":       "../README.md",
+		"nested/dir/down": "../../link-dir/dir/file.txt",
+	}
+
+	mustLink(t, repo, links)
+	mustCommit(t, repository, repo, map[string]string{
+		"README.md":           "Hello world!\n",
+		"nested/dir/file.txt": "foobar\n",
+	})
+
+	if _, err := repository.CreateTag("v1", mustHead(t, repository), nil); err != nil {
+		t.Fatalf("Could not create tag: %v", err)
+	}
+
+	mustLink(t, repo, map[string]string{
+		"absolute": "/etc/passwd",
+		"escape":   "../outside",
+		"loop":     "loop",
+		"missing":  "does-not-exist",
+	})
+	mustCommit(t, repository, repo, nil)
+
+	t.Run("Follow", func(t *testing.T) {
+		t.Parallel()
+
+		fsys, err := Open(repo, "v1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		err = fstest.TestFS(fsys, "link-file", "link-link", "nested/up", "nested/dir/down")
+		if err != nil {
+			t.Fatalf("Unexpected file system: %v", err)
+		}
+
+		want := map[string]string{
+			"link-file":             "Hello world!\n",
+			"link-link":             "Hello world!\n",
+			"link-dir/dir/file.txt": "foobar\n",
+			"nested/up":             "Hello world!\n",
+			"nested/dir/down":       "foobar\n",
+		}
+
+		for name, want := range want {
+			got, _ := fs.ReadFile(fsys, name)
+			if string(got) != want {
+				t.Errorf("Unexpected content of %q (got %q, want %q)", name, got, want)
+			}
+		}
+
+		stat, err := fs.Stat(fsys, "link-dir")
+		if err != nil {
+			t.Fatalf("Could not stat link to directory: %v", err)
+		}
+
+		if !stat.IsDir() {
+			t.Error("Expected link to directory to be a directory")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		fsys, err := Open(repo, "HEAD")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		testCases := map[string]error{
+			"absolute": errEscape,
+			"escape":   errEscape,
+			"loop":     errLinkLoop,
+			"missing":  fs.ErrNotExist,
+		}
+
+		for name, want := range testCases {
+			if _, err := fsys.Open(name); !errors.Is(err, want) {
+				t.Errorf("Unexpected error for %q (got %v, want %v)", name, err, want)
+			}
+		}
+	})
+
+	t.Run("Read link", func(t *testing.T) {
+		t.Parallel()
+
+		fsys, err := Open(repo, "HEAD")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for name, want := range links {
+			got, err := fs.ReadLink(fsys, name)
+			if err != nil {
+				t.Errorf("Could not read link %q: %v", name, err)
+			} else if got != want {
+				t.Errorf("Unexpected target of %q (got %q, want %q)", name, got, want)
+			}
+
+			stat, err := fs.Lstat(fsys, name)
+			if err != nil {
+				t.Errorf("Could not lstat %q: %v", name, err)
+			} else if stat.Mode()&fs.ModeSymlink == 0 {
+				t.Errorf("Expected %q to be a symbolic link", name)
+			}
+		}
+
+		if _, err := fs.ReadLink(fsys, "README.md"); !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Unexpected error for regular file (got %v, want %v)", err, fs.ErrInvalid)
+		}
+	})
+}
+
+func mustCommit(t *testing.T, repository *git.Repository, repo string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		file := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("Could not create directory for %q: %v", name, err)
+		}
+
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatalf("Could not write %q: %v", name, err)
+		}
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatalf("Could not get worktree: %v", err)
+	}
+
+	if err := worktree.AddGlob("."); err != nil {
+		t.Fatalf("Could not stage files: %v", err)
+	}
+
+	opts := git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "ghasum",
+			Email: "ghasum@example.com",
+			When:  time.Now(),
+		},
+	}
+	if _, err := worktree.Commit("commit", &opts); err != nil {
+		t.Fatalf("Could not commit: %v", err)
+	}
+}
+
+func mustHead(t *testing.T, repository *git.Repository) plumbing.Hash {
+	t.Helper()
+
+	head, err := repository.Head()
+	if err != nil {
+		t.Fatalf("Could not get HEAD: %v", err)
+	}
+
+	return head.Hash()
+}
+
+func mustLink(t *testing.T, repo string, links map[string]string) {
+	t.Helper()
+
+	for name, target := range links {
+		link := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+			t.Fatalf("Could not create directory for %q: %v", name, err)
+		}
+
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatalf("Could not create link %q: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// A remote is a location a repository can be cloned from together with the
+// credentials to use for it.
+type remote struct {
+	url  string
+	auth transport.AuthMethod
+}
+
+const (
+	// ServerUrlEnv is the environment variable that holds the URL of the
+	// GitHub instance on GitHub Actions runners.
+	ServerUrlEnv = "GITHUB_SERVER_URL"
+
+	// tokenUsername is the username used for token-based authentication over
+	// HTTPS. GitHub ignores the username when a token is used as password.
+	tokenUsername = "x-access-token"
+
+	passwordKey = "password"
+	sshUser     = "git"
+)
+
+// remotes returns the remotes from which the given repository can be cloned, in
+// order of preference.
+func remotes(repo *Repository) []remote {
+	if repo.URL != "" {
+		return []remote{{url: repo.URL}}
+	}
+
+	https := remote{url: toUrl(repo)}
+	if auth := httpAuth(repo); auth != nil {
+		https.auth = auth
+	}
+
+	remotes := []remote{https}
+	if auth := sshAuth(); auth != nil {
+		remotes = append(remotes, remote{url: toSshUrl(repo), auth: auth})
+	}
+
+	return remotes
+}
+
+// httpAuth returns the credentials to use for the given repository over HTTPS,
+// or nil if no credentials are available.
+//
+// Credentials are taken from, in order, the environment (see [token]), the
+// .netrc file, and the git credential helper.
+func httpAuth(repo *Repository) *http.BasicAuth {
+	u, err := url.Parse(toUrl(repo))
+	if err != nil {
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if token := token(host, repo.Instance); token != "" {
+		return &http.BasicAuth{Username: tokenUsername, Password: token}
+	}
+
+	if login, password, ok := netrc(host); ok {
+		return &http.BasicAuth{Username: login, Password: password}
+	}
+
+	if username, password, ok := credentialHelper(u); ok {
+		return &http.BasicAuth{Username: username, Password: password}
+	}
+
+	return nil
+}
+
+// token returns the token for the given host from the environment, or the zero
+// value if there is none.
+//
+// The GH_TOKEN and GITHUB_TOKEN environment variables are only used for GitHub
+// itself, the given host of the GitHub instance in use, and the host of the
+// [ServerUrlEnv] environment variable, to avoid sending them to other forges.
+func token(host, instance string) string {
+	if host != DefaultHost && !strings.EqualFold(host, instance) && host != serverHost() {
+		return ""
+	}
+
+	for _, key := range []string{"GH_TOKEN", "GITHUB_TOKEN"} {
+		if token := os.Getenv(key); token != "" {
+			return token
+		}
+	}
+
+	return ""
+}
+
+func serverHost() string {
+	u, err := url.Parse(os.Getenv(ServerUrlEnv))
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(u.Host)
+}
+
+// netrc returns the login and password for the given host from the .netrc
+// file, located by the NETRC environment variable or in the home directory.
+func netrc(host string) (login, password string, ok bool) {
+	file := os.Getenv("NETRC")
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", false
+		}
+
+		file = filepath.Join(home, ".netrc")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", "", false
+	}
+
+	return parseNetrc(data, host)
+}
+
+// parseNetrc returns the login and password for the given host from the given
+// .netrc file content. The first matching "machine" entry is used, falling back
+// to the "default" entry.
+func parseNetrc(data []byte, host string) (login, password string, ok bool) {
+	type entry struct {
+		login, password string
+	}
+
+	var (
+		current *entry
+		found   *entry
+		def     *entry
+	)
+
+	tokens := strings.Fields(string(data))
+	for i := 0; i < len(tokens); i++ {
+		var next string
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+
+		switch tokens[i] {
+		case "machine":
+			current = &entry{}
+			if found == nil && strings.EqualFold(next, host) {
+				found = current
+			}
+			i++
+		case "default":
+			current = &entry{}
+			if def == nil {
+				def = current
+			}
+		case "login":
+			if current != nil {
+				current.login = next
+			}
+			i++
+		case passwordKey:
+			if current != nil {
+				current.password = next
+			}
+			i++
+		case "account":
+			i++
+		case "macdef":
+			// macro definitions run until the next empty line, which is lost
+			// when splitting into fields, so stop parsing instead.
+			i = len(tokens)
+		}
+	}
+
+	if found == nil {
+		found = def
+	}
+
+	if found == nil || found.password == "" {
+		return "", "", false
+	}
+
+	return found.login, found.password, true
+}
+
+// credentialHelper returns the username and password for the given repository
+// URL from the git credential helper, if git is available and one is
+// configured.
+func credentialHelper(u *url.URL) (username, password string, ok bool) {
+	path := strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), gitExt)
+
+	var input bytes.Buffer
+	fmt.Fprintf(&input, "protocol=%s\n", u.Scheme)
+	fmt.Fprintf(&input, "host=%s\n", u.Host)
+	fmt.Fprintf(&input, "path=%s%s\n\n", path, gitExt)
+
+	cmd := exec.Command(gitBinary, "credential", "fill")
+	cmd.Stdin = &input
+	cmd.Env = append(os.Environ(), noPrompt, "GCM_INTERACTIVE=never")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", "", false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), "=")
+		switch key {
+		case "username":
+			username = value
+		case passwordKey:
+			password = value
+		}
+	}
+
+	return username, password, password != ""
+}
+
+// sshAuth returns the credentials of the SSH agent, or nil if no agent is
+// running.
+func sshAuth() *ssh.PublicKeysCallback {
+	if os.Getenv("SSH_AUTH_SOCK") == "" {
+		return nil
+	}
+
+	auth, err := ssh.NewSSHAgentAuth(sshUser)
+	if err != nil {
+		return nil
+	}
+
+	return auth
+}
+
+func hostOf(repo *Repository) string {
+	if repo.Host == "" {
+		return DefaultHost
+	}
+
+	return repo.Host
+}
+
+func toSshUrl(repo *Repository) string {
+	url := fmt.Sprintf("ssh://%s@%s/%s/%s%s", sshUser, hostOf(repo), repo.Owner, repo.Project, gitExt)
+	return repo.Rewrites.apply(url)
+}
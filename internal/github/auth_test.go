@@ -0,0 +1,199 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"testing"
+)
+
+func TestParseNetrc(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		data     string
+		host     string
+		login    string
+		password string
+		ok       bool
+	}
+
+	testCases := map[string]TestCase{
+		"single line": {
+			data:     "machine github.com login octocat password secret",
+			host:     "github.com",
+			login:    "octocat",
+			password: "secret",
+			ok:       true,
+		},
+		"multiple lines": {
+			data: `machine ghe.example.com
+  login octocat
+  password secret
+`,
+			host:     "ghe.example.com",
+			login:    "octocat",
+			password: "secret",
+			ok:       true,
+		},
+		"other machine": {
+			data: "machine example.com login octocat password secret",
+			host: "github.com",
+			ok:   false,
+		},
+		"first match": {
+			data: `machine github.com login first password one
+machine github.com login second password two`,
+			host:     "github.com",
+			login:    "first",
+			password: "one",
+			ok:       true,
+		},
+		"default": {
+			data: `machine example.com login octocat password secret
+default login anonymous password guest`,
+			host:     "github.com",
+			login:    "anonymous",
+			password: "guest",
+			ok:       true,
+		},
+		"machine before default": {
+			data: `default login anonymous password guest
+machine github.com login octocat password secret`,
+			host:     "github.com",
+			login:    "octocat",
+			password: "secret",
+			ok:       true,
+		},
+		"case-insensitive host": {
+			data:     "machine GitHub.com login octocat password secret",
+			host:     "github.com",
+			login:    "octocat",
+			password: "secret",
+			ok:       true,
+		},
+		"no password": {
+			data: "machine github.com login octocat",
+			host: "github.com",
+			ok:   false,
+		},
+		"account": {
+			data:     "machine github.com login octocat account foo password secret",
+			host:     "github.com",
+			login:    "octocat",
+			password: "secret",
+			ok:       true,
+		},
+		"macro definition": {
+			data: `machine github.com login octocat password secret
+macdef init
+cd /pub
+
+machine example.com login foo password bar`,
+			host:     "github.com",
+			login:    "octocat",
+			password: "secret",
+			ok:       true,
+		},
+		"empty": {
+			data: "",
+			host: "github.com",
+			ok:   false,
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			login, password, ok := parseNetrc([]byte(tt.data), tt.host)
+			if got, want := ok, tt.ok; got != want {
+				t.Fatalf("Incorrect ok (got %t, want %t)", got, want)
+			}
+
+			if got, want := login, tt.login; got != want {
+				t.Errorf("Incorrect login (got %q, want %q)", got, want)
+			}
+
+			if got, want := password, tt.password; got != want {
+				t.Errorf("Incorrect password (got %q, want %q)", got, want)
+			}
+		})
+	}
+}
+
+func TestToken(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	t.Setenv("GITHUB_SERVER_URL", "https://ghe.example.com")
+
+	testCases := map[string]string{
+		"github.com":         "github-token",
+		"ghe.example.com":    "github-token",
+		"github.example.com": "github-token",
+		"code.forgejo.org":   "",
+	}
+
+	for host, want := range testCases {
+		if got := token(host, "github.example.com"); got != want {
+			t.Errorf("Incorrect result for %q (got %q, want %q)", host, got, want)
+		}
+	}
+
+	t.Run("GH_TOKEN precedence", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "gh-token")
+
+		if got, want := token(DefaultHost, ""), "gh-token"; got != want {
+			t.Errorf("Incorrect result (got %q, want %q)", got, want)
+		}
+	})
+}
+
+func TestToSshUrl(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		in   Repository
+		want string
+	}
+
+	testCases := map[string]TestCase{
+		"GitHub": {
+			in: Repository{
+				Owner:   "chains-project",
+				Project: "ghasum",
+			},
+			want: "ssh://git@github.com/chains-project/ghasum.git",
+		},
+		"other host": {
+			in: Repository{
+				Host:    "code.forgejo.org",
+				Owner:   "actions",
+				Project: "checkout",
+			},
+			want: "ssh://git@code.forgejo.org/actions/checkout.git",
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := toSshUrl(&tt.in)
+			if want := tt.want; got != want {
+				t.Errorf("Incorrect result (got %q, want %q)", got, want)
+			}
+		})
+	}
+}
@@ -15,17 +15,32 @@
 package github
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"path"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 )
 
 // A Repository represents a GitHub repository.
 type Repository struct {
+	// Host is the host of the forge that houses the repository. If this has the
+	// zero value the repository is housed on GitHub.
+	Host string
+
+	// API is the base URL of the REST API of the forge that houses the
+	// repository. If this has the zero value it is derived from the Host.
+	API string
+
+	// Instance is the host of the GitHub instance in use, e.g. a GitHub
+	// Enterprise Server. Tokens from the environment are sent to it in addition
+	// to GitHub itself.
+	Instance string
+
 	// Owner is the name of the user or organization that owns the project.
 	Owner string
 
@@ -34,37 +49,100 @@ type Repository struct {
 
 	// Ref is the reference to check out.
 	Ref string
+
+	// URL is the location to fetch the repository from. If this has the zero
+	// value it is derived from the Host, Owner, and Project.
+	URL string
+
+	// Rewrites are applied to the URLs derived for the repository, including
+	// the URL of the API. They are not applied to the URL field.
+	Rewrites Rewrites
 }
 
-// Clone will clone the given repository at the exact ref from GitHub into the
-// given directory. Note that the git index will be omitted.
-func Clone(dir string, repo *Repository) error {
+// GoGit is a [Fetcher] that clones repositories using go-git.
+//
+// Credentials for private repositories are taken from the environment, the
+// .netrc file, or the git credential helper. If an SSH agent is running, it is
+// used to clone over SSH if cloning over HTTPS fails. Clones that fail due to
+// transient errors, e.g. network errors, are retried.
+type GoGit struct{}
+
+// DefaultHost is the host of GitHub.
+const DefaultHost = "github.com"
+
+// Fetch will clone the given repository at the exact ref into the given
+// directory. Note that the git index will be omitted.
+func (*GoGit) Fetch(dir string, repo *Repository) (Revision, error) {
 	if err := clone(dir, repo); err != nil {
-		return err
+		return Revision{}, err
 	}
 
-	if err := os.RemoveAll(path.Join(dir, ".git")); err != nil {
-		return fmt.Errorf("could not remove git index: %v", err)
+	revision, err := head(dir, repo.Ref)
+	if err != nil {
+		return Revision{}, err
 	}
 
-	return nil
+	return revision, removeIndex(dir)
 }
 
 func clone(dir string, repo *Repository) error {
-	if err := cloneAtTag(dir, repo); err == nil {
+	var (
+		errs     []error
+		notFound bool
+	)
+
+	for _, remote := range remotes(repo) {
+		err := retries.do(func() error {
+			err := cloneFrom(dir, repo, &remote)
+			if err != nil {
+				_ = os.RemoveAll(dir)
+			}
+
+			return err
+		})
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+		notFound = notFound || missing(err, &remote)
+	}
+
+	if notFound {
+		errs = append([]error{ErrNotFound}, errs...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// missing reports whether the given error of cloning from the given remote
+// means that the repository does not exist. GitHub requests authentication for
+// repositories that do not exist if no credentials are used.
+func missing(err error, remote *remote) bool {
+	return errors.Is(err, transport.ErrRepositoryNotFound) ||
+		(remote.auth == nil && errors.Is(err, transport.ErrAuthenticationRequired))
+}
+
+func cloneFrom(dir string, repo *Repository, remote *remote) error {
+	slog.Debug("cloning as tag", logKeyURL, remote.url, logKeyRef, repo.Ref)
+	err := cloneAtTag(dir, repo, remote)
+	if err == nil {
 		return nil
 	}
 
-	if err := cloneAtBranch(dir, repo); err == nil {
+	slog.Debug("cloning as branch", logKeyURL, remote.url, logKeyRef, repo.Ref, logKeyErr, err)
+	if err = cloneAtBranch(dir, repo, remote); err == nil {
 		return nil
 	}
 
-	return cloneAtCommit(dir, repo)
+	slog.Debug("cloning as commit", logKeyURL, remote.url, logKeyRef, repo.Ref, logKeyErr, err)
+	return cloneAtCommit(dir, repo, remote)
 }
 
-func cloneAtBranch(dir string, repo *Repository) error {
+func cloneAtBranch(dir string, repo *Repository, remote *remote) error {
 	opts := git.CloneOptions{
-		URL:           toUrl(repo),
+		URL:           remote.url,
+		Auth:          remote.auth,
 		Depth:         1,
 		SingleBranch:  true,
 		Tags:          git.NoTags,
@@ -73,27 +151,27 @@ func cloneAtBranch(dir string, repo *Repository) error {
 
 	_, err := git.PlainClone(dir, false, &opts)
 	if err != nil {
-		return fmt.Errorf("could not clone %q (as branch) from %q: %v", repo.Ref, opts.URL, err)
+		return fmt.Errorf("could not clone %q (as branch) from %q: %w", repo.Ref, opts.URL, err)
 	}
 
 	return nil
 }
 
-func cloneAtCommit(dir string, repo *Repository) error {
+func cloneAtCommit(dir string, repo *Repository, remote *remote) error {
 	repository, err := git.PlainInit(dir, false)
 	if err != nil {
-		return fmt.Errorf("could not initialize a repository for %s/%s: %v", repo.Owner, repo.Project, err)
+		return fmt.Errorf("could not initialize a repository for %s/%s: %w", repo.Owner, repo.Project, err)
 	}
 
-	remote := "origin"
-	url := toUrl(repo)
+	name := remoteName
+	url := remote.url
 
 	remoteCfg := config.RemoteConfig{
-		Name: remote,
+		Name: name,
 		URLs: []string{url},
 	}
 	if _, err = repository.CreateRemote(&remoteCfg); err != nil {
-		return fmt.Errorf("could not set remote of the repository to %q: %v", url, err)
+		return fmt.Errorf("could not set remote of the repository to %q: %w", url, err)
 	}
 
 	remoteRef := repo.Ref
@@ -101,33 +179,35 @@ func cloneAtCommit(dir string, repo *Repository) error {
 
 	fetchOpts := git.FetchOptions{
 		Depth:      1,
-		RemoteName: remote,
+		RemoteName: name,
+		Auth:       remote.auth,
 		RefSpecs: []config.RefSpec{
 			config.RefSpec(fmt.Sprintf("%s:%s", remoteRef, localRef)),
 		},
 	}
 	if err = repository.Fetch(&fetchOpts); err != nil {
-		return fmt.Errorf("could not fetch commits from %q: %v", url, err)
+		return fmt.Errorf("could not fetch commits from %q: %w", url, err)
 	}
 
 	worktree, err := repository.Worktree()
 	if err != nil {
-		return fmt.Errorf("could not obtain worktree for %s/%s: %v", repo.Owner, repo.Project, err)
+		return fmt.Errorf("could not obtain worktree for %s/%s: %w", repo.Owner, repo.Project, err)
 	}
 
 	checkoutOpts := git.CheckoutOptions{
 		Hash: plumbing.NewHash(repo.Ref),
 	}
 	if err = worktree.Checkout(&checkoutOpts); err != nil {
-		return fmt.Errorf("could not checkout ref %q for %s/%s: %v", repo.Ref, repo.Owner, repo.Project, err)
+		return checkoutFailed(repo, err)
 	}
 
 	return nil
 }
 
-func cloneAtTag(dir string, repo *Repository) error {
+func cloneAtTag(dir string, repo *Repository, remote *remote) error {
 	opts := git.CloneOptions{
-		URL:           toUrl(repo),
+		URL:           remote.url,
+		Auth:          remote.auth,
 		Depth:         1,
 		SingleBranch:  true,
 		Tags:          git.NoTags,
@@ -136,12 +216,17 @@ func cloneAtTag(dir string, repo *Repository) error {
 
 	_, err := git.PlainClone(dir, false, &opts)
 	if err != nil {
-		return fmt.Errorf("could not clone %q (as tag) from %q: %v", repo.Ref, opts.URL, err)
+		return fmt.Errorf("could not clone %q (as tag) from %q: %w", repo.Ref, opts.URL, err)
 	}
 
 	return nil
 }
 
 func toUrl(repo *Repository) (url string) {
-	return fmt.Sprintf("https://github.com/%s/%s", repo.Owner, repo.Project)
+	if repo.URL != "" {
+		return repo.URL
+	}
+
+	url = fmt.Sprintf("https://%s/%s/%s", hostOf(repo), repo.Owner, repo.Project)
+	return repo.Rewrites.apply(url)
 }
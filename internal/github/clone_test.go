@@ -47,6 +47,14 @@ func TestToUrl(t *testing.T) {
 				},
 				want: "https://github.com/with/ref",
 			},
+			"Example with host": {
+				in: Repository{
+					Host:    "code.forgejo.org",
+					Owner:   "actions",
+					Project: "checkout",
+				},
+				want: "https://code.forgejo.org/actions/checkout",
+			},
 		}
 
 		for name, tt := range testCases {
@@ -65,6 +73,7 @@ func TestToUrl(t *testing.T) {
 		t.Parallel()
 
 		isGitHubUrl := func(repo Repository) bool {
+			repo.Host, repo.URL = "", ""
 			url := toUrl(&repo)
 			return strings.HasPrefix(url, "https://github.com/")
 		}
@@ -74,6 +83,7 @@ func TestToUrl(t *testing.T) {
 		}
 
 		containsOwner := func(repo Repository) bool {
+			repo.URL = ""
 			url := toUrl(&repo)
 			return strings.Contains(url, repo.Owner)
 		}
@@ -83,6 +93,7 @@ func TestToUrl(t *testing.T) {
 		}
 
 		containsProject := func(repo Repository) bool {
+			repo.URL = ""
 			url := toUrl(&repo)
 			return strings.Contains(url, repo.Project)
 		}
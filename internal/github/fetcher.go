@@ -0,0 +1,218 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type (
+	// A Fetcher fetches the files of repositories.
+	//
+	// The files fetched for a given repository and ref must be the same
+	// regardless of the Fetcher used, so that checksums computed over them are
+	// the same.
+	Fetcher interface {
+		// Fetch will fetch the files of the given repository at the exact ref
+		// into the given directory, without the git index. It returns the
+		// revision that was fetched, as far as it can be determined.
+		Fetch(dir string, repo *Repository) (Revision, error)
+	}
+
+	// A Revision describes what was fetched for a ref of a repository.
+	Revision struct {
+		// Commit is the commit that was fetched. If this has the zero value the
+		// commit could not be determined.
+		Commit string `json:"commit"`
+
+		// Date is when the revision was created, i.e. the latest of the commit
+		// date and, if the ref is an annotated tag, the date of the tag. If this
+		// has the zero value the date could not be determined.
+		Date time.Time `json:"date,omitzero"`
+
+		// Signed are the objects of the revision that are signed, i.e. the
+		// annotated tag and the commit. If this is nil it could not be
+		// determined whether the revision is signed.
+		Signed []SignedObject `json:"signed,omitzero"`
+	}
+
+	// A SignedObject is a git object together with its signature.
+	SignedObject struct {
+		// Kind is the type of the object, either "commit" or "tag".
+		Kind string `json:"kind"`
+
+		// Payload is the object as it was signed, i.e. without its signature.
+		Payload string `json:"payload"`
+
+		// Signature is the ASCII-armored OpenPGP or SSH signature.
+		Signature string `json:"signature"`
+	}
+)
+
+const (
+	gitBinary = "git"
+	gitDir    = ".git"
+	gitExt    = ".git"
+
+	// fetchHead is the ref that git-fetch(1) writes what it fetched to.
+	fetchHead = "FETCH_HEAD"
+
+	// logKeyErr, logKeyRef, and logKeyURL are the keys of errors, refs, and
+	// URLs in log messages.
+	logKeyErr = "err"
+	logKeyRef = "ref"
+	logKeyURL = "url"
+
+	// remoteName is the name of the remote repositories are fetched from.
+	remoteName = "origin"
+
+	// noPrompt is the environment variable that stops git from prompting for
+	// credentials.
+	noPrompt = "GIT_TERMINAL_PROMPT=0"
+)
+
+// ErrNotFound is the error used when the repository to fetch does not exist.
+// GitHub does not distinguish repositories that do not exist from those that
+// cannot be accessed anonymously, so neither does this.
+var ErrNotFound = errors.New("repository not found")
+
+func checkoutFailed(repo *Repository, err error) error {
+	return fmt.Errorf("could not checkout ref %q for %s/%s: %w", repo.Ref, repo.Owner, repo.Project, err)
+}
+
+func couldNotCreate(path string, err error) error {
+	return fmt.Errorf("could not create %q: %w", path, err)
+}
+
+// head returns the revision checked out in the git repository in the given
+// directory, which was fetched for the given ref. The tag is looked up as the
+// tag ref or, as written by git-fetch(1), in FETCH_HEAD.
+func head(dir, ref string) (Revision, error) {
+	repository, err := git.PlainOpen(dir)
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not open repository in %q: %w", dir, err)
+	}
+
+	reference, err := repository.Head()
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not resolve HEAD in %q: %w", dir, err)
+	}
+
+	commit, err := repository.CommitObject(reference.Hash())
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not read HEAD in %q: %w", dir, err)
+	}
+
+	candidates := make([]plumbing.Hash, 0, 2)
+	if tagRef, refErr := repository.Reference(plumbing.NewTagReferenceName(ref), false); refErr == nil {
+		candidates = append(candidates, tagRef.Hash())
+	}
+
+	if raw, readErr := os.ReadFile(filepath.Join(dir, gitDir, fetchHead)); readErr == nil {
+		if fields := strings.Fields(string(raw)); len(fields) > 0 {
+			candidates = append(candidates, plumbing.NewHash(fields[0]))
+		}
+	}
+
+	var tag *object.Tag
+	for _, hash := range candidates {
+		if tag, err = repository.TagObject(hash); err == nil {
+			break
+		}
+	}
+
+	return released(commit, tag)
+}
+
+// released returns the revision of the given commit, fetched through the given
+// annotated tag if it is not nil.
+func released(commit *object.Commit, tag *object.Tag) (Revision, error) {
+	revision := Revision{
+		Commit: commit.Hash.String(),
+		Date:   commit.Committer.When.UTC(),
+		Signed: make([]SignedObject, 0, 2),
+	}
+
+	if tag != nil {
+		if tag.Tagger.When.After(revision.Date) {
+			revision.Date = tag.Tagger.When.UTC()
+		}
+
+		if tag.PGPSignature != "" {
+			signed, err := signedObject(plumbing.TagObject, tag.PGPSignature, tag.EncodeWithoutSignature)
+			if err != nil {
+				return revision, err
+			}
+
+			revision.Signed = append(revision.Signed, signed)
+		}
+	}
+
+	if commit.PGPSignature != "" {
+		signed, err := signedObject(plumbing.CommitObject, commit.PGPSignature, commit.EncodeWithoutSignature)
+		if err != nil {
+			return revision, err
+		}
+
+		revision.Signed = append(revision.Signed, signed)
+	}
+
+	return revision, nil
+}
+
+// signedObject returns the signed object of the given type, encoded without its
+// signature by encode.
+func signedObject(kind plumbing.ObjectType, signature string, encode func(plumbing.EncodedObject) error) (SignedObject, error) {
+	var encoded plumbing.MemoryObject
+	if err := encode(&encoded); err != nil {
+		return SignedObject{}, fmt.Errorf("could not encode %s: %w", kind, err)
+	}
+
+	var payload []byte
+	reader, err := encoded.Reader()
+	if err == nil {
+		payload, err = io.ReadAll(reader)
+	}
+
+	if err != nil {
+		return SignedObject{}, fmt.Errorf("could not read encoded %s: %w", kind, err)
+	}
+
+	signed := SignedObject{
+		Kind:      kind.String(),
+		Payload:   string(payload),
+		Signature: signature,
+	}
+
+	return signed, nil
+}
+
+func removeIndex(dir string) error {
+	if err := os.RemoveAll(filepath.Join(dir, gitDir)); err != nil {
+		return fmt.Errorf("could not remove git index: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,428 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chains-project/ghasum/internal/checksum"
+	"github.com/chains-project/ghasum/internal/signature"
+)
+
+const (
+	testOwner   = "actions"
+	testProject = "example"
+
+	// testCommitDate and testTagDate are the dates of commits and of annotated
+	// tags in repositories created by newBareRepo.
+	testCommitDate = "2026-01-01T00:00:00Z"
+	testTagDate    = "2026-01-02T00:00:00Z"
+)
+
+var testFiles = map[string]string{
+	"action.yml":       "name: example\nruns:\n  using: node24\n  main: index.js\n",
+	"index.js":         "console.log('Hello world!');\r\n",
+	"nested/file.txt":  "text file\n",
+	"scripts/build.sh": "#!/bin/sh\necho build\n",
+}
+
+func TestFetchers(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	mirror := t.TempDir()
+	bare := filepath.Join(mirror, testOwner, testProject+".git")
+	commit := newBareRepo(t, bare, testFiles)
+
+	server := httptest.NewServer(archiveHandler(bare))
+	t.Cleanup(server.Close)
+
+	fetchers := map[string]Fetcher{
+		"go-git":  &GoGit{},
+		"git":     &Git{},
+		"tarball": &Tarball{Client: server.Client(), BaseURL: server.URL},
+		"mirror":  &Mirror{Dir: mirror},
+	}
+
+	refs := map[string]string{
+		"tag":    "v1",
+		"branch": "main",
+		"commit": commit,
+	}
+
+	for refName, ref := range refs {
+		t.Run(refName, func(t *testing.T) {
+			t.Parallel()
+
+			checksums := make(map[string]string, len(fetchers))
+			revisions := make(map[string]Revision, len(fetchers))
+			for name, fetcher := range fetchers {
+				dir := filepath.Join(t.TempDir(), "out")
+				repo := Repository{
+					Owner:   testOwner,
+					Project: testProject,
+					Ref:     ref,
+					URL:     bare,
+				}
+
+				fetched, err := fetcher.Fetch(dir, &repo)
+				if err != nil {
+					t.Fatalf("Unexpected error for %s: %v", name, err)
+				}
+
+				if _, err = os.Stat(filepath.Join(dir, ".git")); err == nil {
+					t.Errorf("Unexpected git index for %s", name)
+				}
+
+				sum, err := checksum.Compute(dir, checksum.BestAlgo)
+				if err != nil {
+					t.Fatalf("Could not compute checksum for %s: %v", name, err)
+				}
+
+				checksums[name] = sum
+				revisions[name] = fetched
+			}
+
+			want := checksums["go-git"]
+			for name, got := range checksums {
+				if got != want {
+					t.Errorf("Incorrect checksum for %s (got %q, want %q)", name, got, want)
+				}
+			}
+
+			want = revisions["go-git"].Commit
+			if refName != "branch" && want != commit {
+				t.Errorf("Incorrect commit for go-git (got %q, want %q)", want, commit)
+			}
+
+			for name, got := range revisions {
+				if got.Commit != want {
+					t.Errorf("Incorrect commit for %s (got %q, want %q)", name, got.Commit, want)
+				}
+
+				// the date of a tag is not recorded in a tarball
+				date := testCommitDate
+				if refName == "tag" && name != "tarball" {
+					date = testTagDate
+				}
+
+				if got, want := got.Date.Format(time.RFC3339), date; got != want {
+					t.Errorf("Incorrect date for %s (got %q, want %q)", name, got, want)
+				}
+
+				// whether a revision is signed cannot be determined from a tarball
+				if unknown := got.Signed == nil; unknown != (name == "tarball") || len(got.Signed) > 0 {
+					t.Errorf("Incorrect signed objects for %s (got %v)", name, got.Signed)
+				}
+			}
+		})
+	}
+
+	t.Run("rewritten", func(t *testing.T) {
+		t.Parallel()
+
+		rewrites := Rewrites{
+			"https://github.com/": "file://" + filepath.ToSlash(mirror) + "/",
+		}
+
+		for name, fetcher := range map[string]Fetcher{"go-git": &GoGit{}, "git": &Git{}} {
+			repo := Repository{
+				Owner:    testOwner,
+				Project:  testProject + gitExt,
+				Ref:      "v1",
+				Rewrites: rewrites,
+			}
+
+			dir := filepath.Join(t.TempDir(), "out")
+			if _, err := fetcher.Fetch(dir, &repo); err != nil {
+				t.Errorf("Unexpected error for %s: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+
+		for name, fetcher := range fetchers {
+			repo := Repository{
+				Owner:   testOwner,
+				Project: testProject,
+				Ref:     "does-not-exist",
+				URL:     bare,
+			}
+
+			dir := filepath.Join(t.TempDir(), "out")
+			_, err := fetcher.Fetch(dir, &repo)
+			if err == nil {
+				t.Errorf("Unexpected success for %s", name)
+			}
+
+			// for tarballs a missing ref cannot be told apart from a missing repository
+			if errors.Is(err, ErrNotFound) && name != "tarball" {
+				t.Errorf("Unexpected not found error for %s: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		for name, fetcher := range fetchers {
+			repo := Repository{
+				Owner:   testOwner,
+				Project: "does-not-exist",
+				Ref:     "v1",
+				URL:     filepath.Join(mirror, testOwner, "does-not-exist.git"),
+			}
+
+			dir := filepath.Join(t.TempDir(), "out")
+			if _, err := fetcher.Fetch(dir, &repo); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Incorrect error for %s (got %v, want %v)", name, err, ErrNotFound)
+			}
+		}
+	})
+}
+
+func TestFetchersIgnoreAttributes(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	files := map[string]string{
+		".gitattributes": "*.txt text eol=crlf\n*.md export-ignore\n",
+		"file.txt":       "text file\n",
+		"README.md":      "# Example\n",
+	}
+
+	mirror := t.TempDir()
+	bare := filepath.Join(mirror, testOwner, testProject+".git")
+	newBareRepo(t, bare, files)
+
+	fetchers := map[string]Fetcher{
+		"go-git": &GoGit{},
+		"git":    &Git{},
+		"mirror": &Mirror{Dir: mirror},
+	}
+
+	for name, fetcher := range fetchers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := filepath.Join(t.TempDir(), "out")
+			repo := Repository{
+				Owner:   testOwner,
+				Project: testProject,
+				Ref:     "v1",
+				URL:     bare,
+			}
+
+			if _, err := fetcher.Fetch(dir, &repo); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for name, want := range files {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatalf("Could not read %q: %v", name, err)
+				}
+
+				if string(got) != want {
+					t.Errorf("Incorrect content for %q (got %q, want %q)", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchersSigned(t *testing.T) {
+	t.Parallel()
+
+	for _, binary := range []string{"git", "ssh-keygen"} {
+		if _, err := exec.LookPath(binary); err != nil {
+			t.Skipf("%s is not available", binary)
+		}
+	}
+
+	keys := t.TempDir()
+	key := filepath.Join(keys, "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("Could not generate key: %v\n%s", err, out)
+	}
+
+	public, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatalf("Could not read public key: %v", err)
+	}
+
+	work := t.TempDir()
+	if err = os.WriteFile(filepath.Join(work, "action.yml"), []byte(testFiles["action.yml"]), 0o600); err != nil {
+		t.Fatalf("Could not write file: %v", err)
+	}
+
+	signing := []string{"-c", "gpg.format=ssh", "-c", "user.signingKey=" + key}
+	runGit(t, work, "init", "--quiet", "--initial-branch=main")
+	runGit(t, work, "add", ".")
+	runGit(t, work, append(signing, "commit", "--quiet", "--gpg-sign", "--message=Initial commit")...)
+	runGit(t, work, append(signing, "tag", "--sign", "--message=v1", "v1")...)
+
+	mirror := t.TempDir()
+	bare := filepath.Join(mirror, testOwner, testProject+".git")
+	runGit(t, work, "clone", "--quiet", "--bare", work, bare)
+
+	fetchers := map[string]Fetcher{
+		"go-git": &GoGit{},
+		"git":    &Git{},
+		"mirror": &Mirror{Dir: mirror},
+	}
+
+	allowedSigners := []byte("ghasum@example.com " + string(public))
+	for name, fetcher := range fetchers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := filepath.Join(t.TempDir(), "out")
+			repo := Repository{
+				Owner:   testOwner,
+				Project: testProject,
+				Ref:     "v1",
+				URL:     bare,
+			}
+
+			revision, err := fetcher.Fetch(dir, &repo)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			kinds := make([]string, 0, len(revision.Signed))
+			for _, signed := range revision.Signed {
+				kinds = append(kinds, signed.Kind)
+				if _, err := signature.VerifyGit([]byte(signed.Payload), signed.Signature, allowedSigners); err != nil {
+					t.Errorf("Invalid signature for %s: %v", signed.Kind, err)
+				}
+			}
+
+			if got, want := strings.Join(kinds, ","), "tag,commit"; got != want {
+				t.Errorf("Incorrect signed objects (got %q, want %q)", got, want)
+			}
+		})
+	}
+}
+
+// newBareRepo creates a bare git repository at the given path with the given
+// files, with a branch "main" and an annotated tag "v1" that points to an older
+// commit, whose hash is returned.
+func newBareRepo(t *testing.T, bare string, files map[string]string) string {
+	t.Helper()
+
+	work := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(work, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("Could not create directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Could not write file: %v", err)
+		}
+	}
+
+	if err := os.Symlink("nested/file.txt", filepath.Join(work, "link.txt")); err != nil {
+		t.Fatalf("Could not create symlink: %v", err)
+	}
+
+	runGit(t, work, "init", "--quiet", "--initial-branch=main")
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "--quiet", "--message=Initial commit")
+	runGitAt(t, work, testTagDate, "tag", "--annotate", "--message=v1", "v1")
+	commit := runGit(t, work, "rev-parse", "HEAD")
+
+	if err := os.WriteFile(filepath.Join(work, "CHANGELOG.md"), []byte("# Changelog\n"), 0o600); err != nil {
+		t.Fatalf("Could not write file: %v", err)
+	}
+
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "--quiet", "--message=Add changelog")
+
+	runGit(t, work, "clone", "--quiet", "--bare", work, bare)
+	runGit(t, bare, "config", "uploadpack.allowAnySHA1InWant", "true")
+
+	return commit
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	return runGitAt(t, dir, testCommitDate, args...)
+}
+
+// runGitAt runs git with the given date for new commits and tags.
+func runGitAt(t *testing.T, dir, date string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(
+		os.Environ(),
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=ghasum",
+		"GIT_AUTHOR_EMAIL=ghasum@example.com",
+		"GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME=ghasum",
+		"GIT_COMMITTER_EMAIL=ghasum@example.com",
+		"GIT_COMMITTER_DATE="+date,
+	)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// archiveHandler serves tarballs of the given repository like the codeload
+// endpoint of GitHub, at /<owner>/<project>/tar.gz/<ref>.
+func archiveHandler(bare string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if len(parts) != 4 || parts[0] != testOwner || parts[1] != testProject || parts[2] != "tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		prefix := parts[1] + "-" + parts[3] + "/"
+		cmd := exec.Command("git", "-C", bare, "archive", "--format=tar.gz", "--prefix="+prefix, parts[3])
+		out, err := cmd.Output()
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write(out)
+	}
+}
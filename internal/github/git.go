@@ -0,0 +1,117 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Git is a [Fetcher] that fetches repositories using the system git binary.
+//
+// Unlike [GoGit], it relies on the configuration of git for proxies and
+// credentials. Like [GoGit], fetches that fail due to transient errors are
+// retried.
+type Git struct {
+	// Binary is the path to the git binary. If this has the zero value git is
+	// looked up in the PATH.
+	Binary string
+}
+
+// gitAttributes overrides the attributes of all files in the repository, to
+// avoid line ending conversion and filters that would change the content of
+// files compared to other fetchers.
+const gitAttributes = "* -text !eol -filter -ident -working-tree-encoding\n"
+
+// gitNotFound matches the messages of the git binary that indicate that the
+// repository does not exist. Without credentials, GitHub requests them instead,
+// which git fails to read because prompts are disabled.
+var gitNotFound = regexp.MustCompile(`(?i)repository '[^']*' not found|repository not found|does not appear to be a git repository|could not read username`)
+
+// Fetch will fetch the given repository at the exact ref into the given
+// directory. Note that the git index will be omitted.
+func (g *Git) Fetch(dir string, repo *Repository) (Revision, error) {
+	url := toUrl(repo)
+
+	if err := writeDir(dir); err != nil {
+		return Revision{}, err
+	}
+
+	if err := g.run(dir, "init"); err != nil {
+		return Revision{}, fmt.Errorf("could not initialize git in %q: %w", dir, err)
+	}
+
+	attributes := filepath.Join(dir, gitDir, "info", "attributes")
+	if err := writeFile(attributes, strings.NewReader(gitAttributes), 0o600); err != nil {
+		return Revision{}, err
+	}
+
+	slog.Debug("fetching with git", logKeyURL, url, logKeyRef, repo.Ref)
+	err := retries.do(func() error {
+		return g.run(dir, "fetch", "--depth=1", "--no-tags", "--", url, repo.Ref)
+	})
+	if err != nil && gitNotFound.MatchString(err.Error()) {
+		return Revision{}, fmt.Errorf("could not fetch %q from %q: %w: %w", repo.Ref, url, ErrNotFound, err)
+	} else if err != nil {
+		return Revision{}, fmt.Errorf("could not fetch %q from %q: %w", repo.Ref, url, err)
+	}
+
+	if err = g.run(dir, "checkout", "--detach", fetchHead); err != nil {
+		return Revision{}, checkoutFailed(repo, err)
+	}
+
+	revision, err := head(dir, repo.Ref)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	return revision, removeIndex(dir)
+}
+
+func (g *Git) run(dir, command string, args ...string) error {
+	binary := g.Binary
+	if binary == "" {
+		binary = gitBinary
+	}
+
+	args = append([]string{
+		"-C", dir,
+		"-c", "advice.detachedHead=false",
+		"-c", "core.autocrlf=false",
+		"-c", "core.symlinks=true",
+		command, "--quiet",
+	}, args...)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(binary, args...)
+	cmd.Env = append(os.Environ(), noPrompt, "GIT_LFS_SKIP_SMUDGE=1")
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git %s failed: %w: %s", command, err, msg)
+		}
+
+		return fmt.Errorf("git %s failed: %w", command, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type (
+	graphqlRequest struct {
+		Query     string            `json:"query"`
+		Variables map[string]string `json:"variables"`
+	}
+
+	graphqlResponse struct {
+		Data   map[string]*graphqlRepository `json:"data"`
+		Errors []graphqlError                `json:"errors"`
+	}
+
+	graphqlRepository struct {
+		IsArchived       bool   `json:"isArchived"`
+		NameWithOwner    string `json:"nameWithOwner"`
+		Visibility       string `json:"visibility"`
+		DefaultBranchRef *struct {
+			Name string `json:"name"`
+		} `json:"defaultBranchRef"`
+	}
+
+	graphqlError struct {
+		Message string `json:"message"`
+	}
+)
+
+// graphqlFields are the fields of a repository that are queried.
+const graphqlFields = "isArchived nameWithOwner visibility defaultBranchRef { name }"
+
+// queryMetadata looks up the metadata of the given repositories in a single
+// query to the GraphQL API at the given endpoint. Repositories that do not exist
+// are omitted.
+func queryMetadata(endpoint, secret string, repos []Repository) (map[RepoID]Metadata, error) {
+	var (
+		params  = make([]string, 0, 2*len(repos))
+		fields  = make([]string, 0, len(repos))
+		request = graphqlRequest{Variables: make(map[string]string, 2*len(repos))}
+	)
+
+	for i, repo := range repos {
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!", i, i))
+		fields = append(fields, fmt.Sprintf("r%d: repository(owner: $o%d, name: $n%d) { %s }", i, i, i, graphqlFields))
+		request.Variables[fmt.Sprintf("o%d", i)] = repo.Owner
+		request.Variables[fmt.Sprintf("n%d", i)] = repo.Project
+	}
+
+	request.Query = fmt.Sprintf("query(%s) { %s }", strings.Join(params, ", "), strings.Join(fields, " "))
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode query: %w", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := send(httpClient, req, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var response graphqlResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("POST %s response malformed: %w", endpoint, err)
+	}
+
+	if response.Data == nil {
+		messages := make([]string, len(response.Errors))
+		for i, e := range response.Errors {
+			messages[i] = e.Message
+		}
+
+		return nil, fmt.Errorf("POST %s failed: %s", endpoint, strings.Join(messages, "; "))
+	}
+
+	metadata := make(map[RepoID]Metadata, len(repos))
+	for i, repo := range repos {
+		found := response.Data[fmt.Sprintf("r%d", i)]
+		if found == nil {
+			continue
+		}
+
+		var defaultBranch string
+		if found.DefaultBranchRef != nil {
+			defaultBranch = found.DefaultBranchRef.Name
+		}
+
+		metadata[repo.ID()] = Metadata{
+			Archived:      found.IsArchived,
+			DefaultBranch: defaultBranch,
+			Visibility:    strings.ToLower(found.Visibility),
+			RenamedTo:     renamedTo(&repo, found.NameWithOwner),
+		}
+	}
+
+	return metadata, nil
+}
+
+// graphqlUrl returns the URL of the GraphQL API of the forge that houses the
+// given repository.
+func graphqlUrl(repo *Repository) string {
+	return repo.Rewrites.apply(canonicalGraphqlUrl(repo))
+}
+
+func canonicalGraphqlUrl(repo *Repository) string {
+	api := canonicalApiUrl(repo)
+	if base, ok := strings.CutSuffix(api, "/v3"); ok {
+		api = base
+	}
+
+	return api + "/graphql"
+}
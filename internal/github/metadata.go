@@ -16,54 +16,235 @@ package github
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
-	"os"
+	"slices"
+	"strings"
+	"sync"
 )
 
-type apiRepoMetadata struct {
-	Archived bool `json:"archived"`
+type (
+	// Metadata is the metadata of a repository.
+	Metadata struct {
+		// Archived is whether the repository is archived.
+		Archived bool
+
+		// DefaultBranch is the name of the default branch of the repository.
+		DefaultBranch string
+
+		// Visibility is the visibility of the repository, one of "public",
+		// "private", or "internal".
+		Visibility string
+
+		// RenamedTo is the current name of the repository, as owner/project,
+		// if it was renamed or transferred. Otherwise it has the zero value.
+		RenamedTo string
+	}
+
+	// A RepoID identifies a repository regardless of the ref.
+	RepoID struct {
+		Host    string
+		Owner   string
+		Project string
+	}
+
+	apiRepoMetadata struct {
+		Archived      bool   `json:"archived"`
+		DefaultBranch string `json:"default_branch"`
+		FullName      string `json:"full_name"`
+		Visibility    string `json:"visibility"`
+	}
+)
+
+const (
+	// batchSize is the maximum number of repositories in one GraphQL query.
+	batchSize = 50
+
+	// concurrency is the maximum number of concurrent API requests.
+	concurrency = 4
+)
+
+// errStatusNotFound is the error used when a request fails because the
+// requested resource does not exist.
+var errStatusNotFound = errors.New("status 404")
+
+// ID returns the identifier of the repository. Because GitHub is case
+// insensitive, the owner and project are lowercased.
+func (r *Repository) ID() RepoID {
+	return RepoID{
+		Host:    hostOf(r),
+		Owner:   strings.ToLower(r.Owner),
+		Project: strings.ToLower(r.Project),
+	}
 }
 
-const apiUrl = "https://api.github.com"
+// LookupMetadata returns the metadata of the given repositories on GitHub, or
+// the GitHub Enterprise Server that houses them. Repositories for which the
+// metadata cannot be obtained are omitted.
+//
+// Every repository is looked up once. If a token is available repositories are
+// looked up in batches using the GraphQL API, otherwise they are looked up one
+// by one using the REST API. Requests are made concurrently. Both APIs follow
+// renamed and transferred repositories to their current name, which is how
+// these are detected.
+func LookupMetadata(repos []Repository) map[RepoID]Metadata {
+	seen := make(map[RepoID]struct{}, len(repos))
+	groups := make(map[string][]Repository)
+	for _, repo := range repos {
+		if _, ok := seen[repo.ID()]; ok {
+			continue
+		}
 
-// Archived returns whether the given repository is archived on GitHub.
-func Archived(repo *Repository) (bool, error) {
-	metadata, err := getRepoMetadata(repo)
-	if err != nil {
-		return false, err
+		seen[repo.ID()] = struct{}{}
+		endpoint := graphqlUrl(&repo)
+		groups[endpoint] = append(groups[endpoint], repo)
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, concurrency)
+		metadata  = make(map[RepoID]Metadata, len(seen))
+	)
+
+	store := func(found map[RepoID]Metadata) {
+		mu.Lock()
+		defer mu.Unlock()
+		maps.Copy(metadata, found)
+	}
+
+	for endpoint, group := range groups {
+		secret := apiToken(&group[0], canonicalGraphqlUrl(&group[0]))
+		for batch := range slices.Chunk(group, batchSize) {
+			wg.Go(func() {
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				if secret != "" {
+					if found, err := queryMetadata(endpoint, secret, batch); err == nil {
+						store(found)
+						return
+					}
+				}
+
+				store(restMetadata(batch))
+			})
+		}
+	}
+
+	wg.Wait()
+	return metadata
+}
+
+// restMetadata looks up the metadata of the given repositories one by one
+// using the REST API.
+func restMetadata(repos []Repository) map[RepoID]Metadata {
+	metadata := make(map[RepoID]Metadata, len(repos))
+	for _, repo := range repos {
+		if found, err := getRepoMetadata(&repo); err == nil {
+			metadata[repo.ID()] = Metadata{
+				Archived:      found.Archived,
+				DefaultBranch: found.DefaultBranch,
+				Visibility:    strings.ToLower(found.Visibility),
+				RenamedTo:     renamedTo(&repo, found.FullName),
+			}
+		}
+	}
+
+	return metadata
+}
+
+// renamedTo returns the given full name of the repository if it differs from
+// the name of the given repository, and the zero value otherwise.
+func renamedTo(repo *Repository, fullName string) string {
+	if fullName == "" || strings.EqualFold(fullName, repo.Owner+"/"+repo.Project) {
+		return ""
 	}
 
-	return metadata.Archived, nil
+	return fullName
 }
 
 func getRepoMetadata(repo *Repository) (apiRepoMetadata, error) {
 	var metadata apiRepoMetadata
 
-	url := fmt.Sprintf("%s/repos/%s/%s", apiUrl, repo.Owner, repo.Project)
-	req, _ := http.NewRequest("GET", url, nil)
+	url := fmt.Sprintf("%s/repos/%s/%s", apiUrl(repo), repo.Owner, repo.Project)
+	resp, err := send(httpClient, restRequest(url), apiToken(repo, canonicalApiUrl(repo)))
+	if err != nil {
+		return metadata, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	err = json.NewDecoder(resp.Body).Decode(&metadata)
+	if err != nil {
+		return metadata, fmt.Errorf("GET %s response malformed: %w", url, err)
+	}
+
+	return metadata, nil
+}
+
+func apiUrl(repo *Repository) string {
+	return repo.Rewrites.apply(canonicalApiUrl(repo))
+}
+
+// apiToken returns the token to use for the given canonical URL of an API of the
+// given repository. To avoid leaking it, no token is used if the URL is
+// rewritten.
+func apiToken(repo *Repository, url string) string {
+	if repo.Rewrites.apply(url) != url {
+		return ""
+	}
+
+	return token(hostOf(repo), repo.Instance)
+}
+
+func canonicalApiUrl(repo *Repository) string {
+	switch {
+	case repo.API != "":
+		return strings.TrimSuffix(repo.API, "/")
+	case repo.Host == "" || repo.Host == DefaultHost:
+		return "https://api.github.com"
+	default:
+		return "https://" + repo.Host + "/api/v3"
+	}
+}
 
+// restRequest creates a GET request for the given URL of the REST API.
+func restRequest(url string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
 	req.Header.Add("Accept", "application/vnd.github+json")
 	req.Header.Add("X-GitHub-Api-Version", "2022-11-28")
-	if token, ok := os.LookupEnv("GH_TOKEN"); ok {
-		req.Header.Add("Authorization", "Bearer "+token)
+	return req
+}
+
+// send performs the given request using the given token, if any, failing if
+// the response does not have status 200.
+func send(client *http.Client, req *http.Request, secret string) (*http.Response, error) {
+	if secret != "" {
+		req.Header.Add("Authorization", "Bearer "+secret)
 	}
 
-	client := &http.Client{}
+	url := req.URL.String()
 	resp, err := client.Do(req)
 	if err != nil {
-		return metadata, fmt.Errorf("GET %s failed: %v", url, err)
+		return nil, fmt.Errorf("%s request to %s failed: %w", req.Method, url, err)
 	}
 
-	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusOK {
-		return metadata, fmt.Errorf("GET %s failed with status %d", url, resp.StatusCode)
-	}
+		_ = resp.Body.Close()
 
-	err = json.NewDecoder(resp.Body).Decode(&metadata)
-	if err != nil {
-		return metadata, fmt.Errorf("GET %s response malformed: %v", url, err)
+		var reason string
+		if rateLimited(resp) {
+			reason = " (rate limit exceeded)"
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%s request to %s failed with %w", req.Method, url, errStatusNotFound)
+		}
+
+		return nil, fmt.Errorf("%s request to %s failed with status %d%s", req.Method, url, resp.StatusCode, reason)
 	}
 
-	return metadata, nil
+	return resp, nil
 }
@@ -0,0 +1,371 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestApiUrl(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		in   Repository
+		want string
+	}
+
+	testCases := map[string]TestCase{
+		"GitHub, implicit": {
+			in:   Repository{},
+			want: "https://api.github.com",
+		},
+		"GitHub, explicit": {
+			in: Repository{
+				Host: "github.com",
+			},
+			want: "https://api.github.com",
+		},
+		"GitHub Enterprise Server": {
+			in: Repository{
+				Host: "ghe.example.com",
+			},
+			want: "https://ghe.example.com/api/v3",
+		},
+		"explicit API": {
+			in: Repository{
+				Host: "ghe.example.com",
+				API:  "https://api.ghe.example.com/",
+			},
+			want: "https://api.ghe.example.com",
+		},
+		"rewritten": {
+			in: Repository{
+				Rewrites: Rewrites{
+					"https://api.github.com": "https://git-mirror.example.com/api",
+				},
+			},
+			want: "https://git-mirror.example.com/api",
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := apiUrl(&tt.in)
+			if want := tt.want; got != want {
+				t.Errorf("Incorrect result (got %q, want %q)", got, want)
+			}
+		})
+	}
+}
+
+func TestApiToken(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "github-token")
+
+	type TestCase struct {
+		rewrites Rewrites
+		rest     string
+		graphql  string
+	}
+
+	testCases := map[string]TestCase{
+		"not rewritten": {
+			rest:    "github-token",
+			graphql: "github-token",
+		},
+		"API rewritten": {
+			rewrites: Rewrites{
+				"https://api.github.com": "https://git-mirror.example.com/api",
+			},
+			rest:    "",
+			graphql: "",
+		},
+		"GraphQL API rewritten": {
+			rewrites: Rewrites{
+				"https://api.github.com/graphql": "https://git-mirror.example.com/graphql",
+			},
+			rest:    "github-token",
+			graphql: "",
+		},
+	}
+
+	for name, tt := range testCases {
+		repo := Repository{Rewrites: tt.rewrites}
+
+		if got, want := apiToken(&repo, canonicalApiUrl(&repo)), tt.rest; got != want {
+			t.Errorf("Incorrect token for the REST API for %q (got %q, want %q)", name, got, want)
+		}
+
+		if got, want := apiToken(&repo, canonicalGraphqlUrl(&repo)), tt.graphql; got != want {
+			t.Errorf("Incorrect token for the GraphQL API for %q (got %q, want %q)", name, got, want)
+		}
+	}
+}
+
+func TestLookupMetadata(t *testing.T) {
+	archived := map[string]bool{
+		"owner/archived": true,
+		"owner/active":   false,
+	}
+
+	newServer := func(t *testing.T, graphql bool) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+		t.Helper()
+
+		var queries, requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/graphql":
+				queries.Add(1)
+				if !graphql {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				var request graphqlRequest
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				data := make(map[string]*graphqlRepository)
+				for i := 0; ; i++ {
+					owner, ok := request.Variables[fmt.Sprintf("o%d", i)]
+					if !ok {
+						break
+					}
+
+					name := owner + "/" + request.Variables[fmt.Sprintf("n%d", i)]
+					if isArchived, ok := archived[strings.ToLower(name)]; ok {
+						data[fmt.Sprintf("r%d", i)] = &graphqlRepository{IsArchived: isArchived}
+					} else {
+						data[fmt.Sprintf("r%d", i)] = nil
+					}
+				}
+
+				_ = json.NewEncoder(w).Encode(graphqlResponse{Data: data})
+			case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/repos/"):
+				requests.Add(1)
+
+				isArchived, ok := archived[strings.ToLower(strings.TrimPrefix(r.URL.Path, "/repos/"))]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				_ = json.NewEncoder(w).Encode(apiRepoMetadata{Archived: isArchived})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		return server, &queries, &requests
+	}
+
+	repos := func(api string) []Repository {
+		repos := make([]Repository, 0, 4+2*batchSize)
+		repos = append(repos,
+			Repository{API: api, Owner: "owner", Project: "archived", Ref: "v1"},
+			Repository{API: api, Owner: "owner", Project: "archived", Ref: "v2"},
+			Repository{API: api, Owner: "Owner", Project: "Active", Ref: "v1"},
+			Repository{API: api, Owner: "owner", Project: "missing", Ref: "v1"},
+		)
+
+		for i := range 2 * batchSize {
+			repos = append(repos, Repository{API: api, Owner: "other", Project: fmt.Sprintf("p%d", i)})
+		}
+
+		return repos
+	}
+
+	check := func(t *testing.T, got map[RepoID]Metadata) {
+		t.Helper()
+
+		want := map[RepoID]Metadata{
+			{Host: DefaultHost, Owner: "owner", Project: "archived"}: {Archived: true},
+			{Host: DefaultHost, Owner: "owner", Project: "active"}:   {Archived: false},
+		}
+
+		if len(got) != len(want) {
+			t.Errorf("Incorrect number of results (got %d, want %d)", len(got), len(want))
+		}
+
+		for id, want := range want {
+			if got, ok := got[id]; !ok || got != want {
+				t.Errorf("Incorrect result for %v (got %v, want %v)", id, got, want)
+			}
+		}
+	}
+
+	t.Run("GraphQL", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "token")
+
+		server, queries, requests := newServer(t, true)
+		check(t, LookupMetadata(repos(server.URL)))
+
+		if got, want := queries.Load(), int32(3); got != want {
+			t.Errorf("Incorrect number of queries (got %d, want %d)", got, want)
+		}
+
+		if got, want := requests.Load(), int32(0); got != want {
+			t.Errorf("Incorrect number of REST requests (got %d, want %d)", got, want)
+		}
+	})
+
+	t.Run("GraphQL unavailable", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "token")
+
+		server, queries, requests := newServer(t, false)
+		check(t, LookupMetadata(repos(server.URL)))
+
+		if got, want := queries.Load(), int32(3); got != want {
+			t.Errorf("Incorrect number of queries (got %d, want %d)", got, want)
+		}
+
+		if got, want := requests.Load(), int32(3+2*batchSize); got != want {
+			t.Errorf("Incorrect number of REST requests (got %d, want %d)", got, want)
+		}
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GITHUB_TOKEN", "")
+
+		server, queries, requests := newServer(t, true)
+		check(t, LookupMetadata(repos(server.URL)))
+
+		if got, want := queries.Load(), int32(0); got != want {
+			t.Errorf("Incorrect number of queries (got %d, want %d)", got, want)
+		}
+
+		if got, want := requests.Load(), int32(3+2*batchSize); got != want {
+			t.Errorf("Incorrect number of REST requests (got %d, want %d)", got, want)
+		}
+	})
+}
+
+func TestLookupMetadataMoved(t *testing.T) {
+	newServer := func(t *testing.T) *httptest.Server {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/graphql":
+				data := map[string]*graphqlRepository{
+					"r0": {NameWithOwner: "new-owner/renamed"},
+					"r1": {NameWithOwner: "Owner/Same"},
+				}
+
+				_ = json.NewEncoder(w).Encode(graphqlResponse{Data: data})
+			case r.URL.Path == "/repos/owner/moved":
+				http.Redirect(w, r, "/repositories/42", http.StatusMovedPermanently)
+			case r.URL.Path == "/repositories/42":
+				_ = json.NewEncoder(w).Encode(apiRepoMetadata{FullName: "new-owner/renamed"})
+			case r.URL.Path == "/repos/owner/same":
+				_ = json.NewEncoder(w).Encode(apiRepoMetadata{FullName: "Owner/Same"})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	check := func(t *testing.T, got map[RepoID]Metadata) {
+		t.Helper()
+
+		want := map[RepoID]Metadata{
+			{Host: DefaultHost, Owner: "owner", Project: "moved"}: {RenamedTo: "new-owner/renamed"},
+			{Host: DefaultHost, Owner: "owner", Project: "same"}:  {RenamedTo: ""},
+		}
+
+		for id, want := range want {
+			if got, ok := got[id]; !ok || got != want {
+				t.Errorf("Incorrect result for %v (got %v, want %v)", id, got, want)
+			}
+		}
+	}
+
+	repos := func(api string) []Repository {
+		return []Repository{
+			{API: api, Owner: "owner", Project: "moved"},
+			{API: api, Owner: "owner", Project: "same"},
+		}
+	}
+
+	t.Run("GraphQL", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "token")
+
+		server := newServer(t)
+		check(t, LookupMetadata(repos(server.URL)))
+	})
+
+	t.Run("REST", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GITHUB_TOKEN", "")
+
+		server := newServer(t)
+		check(t, LookupMetadata(repos(server.URL)))
+	})
+}
+
+func TestGraphqlUrl(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		in   Repository
+		want string
+	}
+
+	testCases := map[string]TestCase{
+		"GitHub": {
+			in:   Repository{},
+			want: "https://api.github.com/graphql",
+		},
+		"GitHub Enterprise Server": {
+			in: Repository{
+				Host: "ghe.example.com",
+			},
+			want: "https://ghe.example.com/api/graphql",
+		},
+		"explicit API": {
+			in: Repository{
+				Host: "ghe.example.com",
+				API:  "https://ghe.example.com/api/v3/",
+			},
+			want: "https://ghe.example.com/api/graphql",
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := graphqlUrl(&tt.in)
+			if want := tt.want; got != want {
+				t.Errorf("Incorrect result (got %q, want %q)", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Mirror is a [Fetcher] that reads repositories from a local directory of
+// (bare) git repositories, without using the network.
+//
+// The repository owner/project is expected at owner/project.git or
+// owner/project in the directory, prefixed by the host for repositories that
+// are not on GitHub.
+type Mirror struct {
+	// Dir is the path to the mirror directory.
+	Dir string
+}
+
+// Fetch will write the files of the given repository at the exact ref from the
+// mirror into the given directory.
+func (m *Mirror) Fetch(dir string, repo *Repository) (Revision, error) {
+	repository, err := m.open(repo)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	commit, tag, err := resolve(repository, repo.Ref)
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not resolve ref %q for %s/%s: %w", repo.Ref, repo.Owner, repo.Project, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not read tree of %q for %s/%s: %w", repo.Ref, repo.Owner, repo.Project, err)
+	}
+
+	err = tree.Files().ForEach(func(file *object.File) error {
+		return writeObject(dir, file)
+	})
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not write files of %s/%s: %w", repo.Owner, repo.Project, err)
+	}
+
+	return released(commit, tag)
+}
+
+func (m *Mirror) open(repo *Repository) (*git.Repository, error) {
+	base := filepath.Join(m.Dir, repo.Owner, repo.Project)
+	if host := hostOf(repo); host != DefaultHost {
+		base = filepath.Join(m.Dir, host, repo.Owner, repo.Project)
+	}
+
+	var err error
+	for _, candidate := range []string{base + gitExt, base} {
+		var repository *git.Repository
+		if repository, err = git.PlainOpen(candidate); err == nil {
+			return repository, nil
+		}
+	}
+
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		err = ErrNotFound
+	}
+
+	return nil, fmt.Errorf("could not open %s/%s from mirror: %w", repo.Owner, repo.Project, err)
+}
+
+// resolve returns the commit the given ref refers to and, if the ref is an
+// annotated tag, the tag.
+func resolve(repository *git.Repository, ref string) (*object.Commit, *object.Tag, error) {
+	candidates := []plumbing.ReferenceName{
+		plumbing.NewTagReferenceName(ref),
+		plumbing.NewBranchReferenceName(ref),
+	}
+
+	hash := plumbing.NewHash(ref)
+	for _, name := range candidates {
+		if reference, err := repository.Reference(name, true); err == nil {
+			hash = reference.Hash()
+			break
+		}
+	}
+
+	var (
+		commit *object.Commit
+		err    error
+	)
+
+	tag, tagErr := repository.TagObject(hash)
+	if tagErr == nil {
+		commit, err = tag.Commit()
+	} else {
+		tag = nil
+		commit, err = repository.CommitObject(hash)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read commit %s: %w", hash, err)
+	}
+
+	return commit, tag, nil
+}
+
+func writeObject(dir string, file *object.File) error {
+	reader, err := file.Reader()
+	if err != nil {
+		return fmt.Errorf("could not open %q: %w", file.Name, err)
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	target := filepath.Join(dir, filepath.FromSlash(file.Name))
+	if file.Mode == filemode.Symlink {
+		var link []byte
+		if link, err = io.ReadAll(reader); err != nil {
+			return fmt.Errorf("could not read %q: %w", file.Name, err)
+		}
+
+		return writeSymlink(target, string(link))
+	}
+
+	mode, err := file.Mode.ToOSFileMode()
+	if err != nil {
+		return fmt.Errorf("invalid mode of %q: %w", file.Name, err)
+	}
+
+	return writeFile(target, reader, mode)
+}
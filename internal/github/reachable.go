@@ -0,0 +1,168 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"golang.org/x/mod/semver"
+)
+
+type apiComparison struct {
+	Status string `json:"status"`
+}
+
+const (
+	// maxComparisons is the maximum number of branches and tags a commit is
+	// compared to, bounding the number of requests to the REST API.
+	maxComparisons = 5
+
+	// recentTags is the number of most recent tags a commit is compared to
+	// before the default branch.
+	recentTags = 3
+)
+
+// IsCommit reports whether the given ref is a full commit SHA, using either
+// SHA-1 or SHA-256.
+func IsCommit(ref string) bool {
+	if len(ref) != 40 && len(ref) != 64 {
+		return false
+	}
+
+	return strings.Trim(ref, "0123456789abcdef") == ""
+}
+
+// Reachable reports whether the commit the Ref of the given repository refers
+// to is reachable from a branch or tag of the repository itself. A commit that
+// is not reachable may come from a fork of the repository, i.e. it may be an
+// impostor commit.
+//
+// Branches and tags that point to the commit are found by listing the refs of
+// the repository. Otherwise, the commit is compared to the most recent tags,
+// the default branch, and then the other branches and tags, using the REST API
+// of the GitHub instance. Only a few comparisons are made, if the commit is not
+// found to be reachable by then an error is returned.
+func Reachable(repo *Repository) (bool, error) {
+	refs, err := lsRemote(repo)
+	if err != nil {
+		return false, err
+	}
+
+	bases := candidates(refs)
+	if slices.Contains(bases, repo.Ref) {
+		return true, nil
+	}
+
+	for i, base := range bases {
+		if i == maxComparisons {
+			return false, fmt.Errorf("not reachable from the %d branches and tags it was compared to", i)
+		}
+
+		status, err := compareCommits(repo, base)
+		if err != nil {
+			return false, err
+		}
+
+		// "behind" means the commit is an ancestor of the base
+		if status == "behind" || status == "identical" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// candidates returns the unique commits that the branches and tags in the given
+// refs point to, starting with the most recent tags, followed by the default
+// branch, and then the other branches and tags.
+func candidates(refs []*plumbing.Reference) []string {
+	var (
+		head     plumbing.ReferenceName
+		detached string
+		branches = make(map[string]string, 0)
+	)
+
+	for _, ref := range refs {
+		switch {
+		case ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference:
+			head = ref.Target()
+		case ref.Name() == plumbing.HEAD:
+			detached = ref.Hash().String()
+		case ref.Name().IsBranch():
+			branches[ref.Name().String()] = ref.Hash().String()
+		}
+	}
+
+	tagged := tags(refs)
+	names := slices.SortedFunc(maps.Keys(tagged), func(a, b string) int {
+		if c := semver.Compare(b, a); c != 0 {
+			return c
+		}
+
+		return strings.Compare(b, a)
+	})
+
+	var commits []string
+	for _, name := range names[:min(recentTags, len(names))] {
+		commits = append(commits, tagged[name])
+	}
+
+	if detached != "" {
+		commits = append(commits, detached)
+	}
+
+	if commit, ok := branches[head.String()]; ok {
+		commits = append(commits, commit)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(branches)) {
+		commits = append(commits, branches[name])
+	}
+
+	for _, name := range names[min(recentTags, len(names)):] {
+		commits = append(commits, tagged[name])
+	}
+
+	unique := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		if !slices.Contains(unique, commit) {
+			unique = append(unique, commit)
+		}
+	}
+
+	return unique
+}
+
+func compareCommits(repo *Repository, base string) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", apiUrl(repo), repo.Owner, repo.Project, base, repo.Ref)
+	resp, err := send(httpClient, restRequest(url), apiToken(repo, canonicalApiUrl(repo)))
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var comparison apiComparison
+	if err := json.NewDecoder(resp.Body).Decode(&comparison); err != nil {
+		return "", fmt.Errorf("could not parse comparison from %s: %w", url, err)
+	}
+
+	return comparison.Status, nil
+}
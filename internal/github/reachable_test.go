@@ -0,0 +1,175 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIsCommit(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]bool{
+		"b4ffde65f46336ab88eb53be808477a3936bae11":                         true,
+		"9e8b2a5fd1d7b84bbd3c8e3a2ee4fcbc8c1d7b4a06a3c1a2e7f90ff2ec5e1a77": true,
+		"v4.1.1":  false,
+		"b4ffde6": false,
+		"B4FFDE65F46336AB88EB53BE808477A3936BAE11": false,
+		"g4ffde65f46336ab88eb53be808477a3936bae11": false,
+	}
+
+	for ref, want := range testCases {
+		if got := IsCommit(ref); got != want {
+			t.Errorf("Incorrect result for %q (got %t, want %t)", ref, got, want)
+		}
+	}
+}
+
+func TestReachable(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	var (
+		ancestor = strings.Repeat("a", 40)
+		impostor = strings.Repeat("b", 40)
+		missing  = strings.Repeat("c", 40)
+		released = strings.Repeat("d", 40)
+	)
+
+	bare := filepath.Join(t.TempDir(), testOwner, testProject+".git")
+	tagged := newBareRepo(t, bare, testFiles)
+	head := runGit(t, bare, "rev-parse", "main")
+
+	many := filepath.Join(t.TempDir(), testOwner, testProject+".git")
+	runGit(t, bare, "clone", "--quiet", "--bare", bare, many)
+	tree := runGit(t, many, "rev-parse", "main^{tree}")
+	for i := range maxComparisons {
+		commit := runGit(t, many, "commit-tree", "-m", strconv.Itoa(i), tree)
+		runGit(t, many, "branch", "branch-"+strconv.Itoa(i), commit)
+	}
+
+	setup := func(t *testing.T, dir string) (*Repository, *atomic.Int32) {
+		t.Helper()
+
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			_, comparison, _ := strings.Cut(r.URL.Path, "/compare/")
+			base, commit, _ := strings.Cut(comparison, "...")
+
+			status := "diverged"
+			switch {
+			case commit == missing:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			case commit == ancestor && base == head:
+				status = "behind"
+			case commit == released && base == tagged:
+				status = "behind"
+			}
+
+			_ = json.NewEncoder(w).Encode(apiComparison{Status: status})
+		}))
+		t.Cleanup(server.Close)
+
+		repo := Repository{API: server.URL, Owner: testOwner, Project: testProject, URL: dir}
+		return &repo, &requests
+	}
+
+	type TestCase struct {
+		bare      string
+		ref       string
+		want      bool
+		requests  int32
+		wantError bool
+	}
+
+	testCases := map[string]TestCase{
+		"tagged": {
+			ref:      tagged,
+			want:     true,
+			requests: 0,
+		},
+		"branch": {
+			ref:      head,
+			want:     true,
+			requests: 0,
+		},
+		"ancestor of tag": {
+			ref:      released,
+			want:     true,
+			requests: 1,
+		},
+		"ancestor of default branch": {
+			ref:      ancestor,
+			want:     true,
+			requests: 2,
+		},
+		"impostor": {
+			ref:      impostor,
+			want:     false,
+			requests: 2,
+		},
+		"too many branches": {
+			bare:      many,
+			ref:       impostor,
+			requests:  maxComparisons,
+			wantError: true,
+		},
+		"comparison fails": {
+			ref:       missing,
+			requests:  1,
+			wantError: true,
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.bare == "" {
+				tt.bare = bare
+			}
+
+			repo, requests := setup(t, tt.bare)
+			repo.Ref = tt.ref
+
+			got, err := Reachable(repo)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Incorrect result (got %t, want %t)", got, tt.want)
+			}
+
+			if got, want := requests.Load(), tt.requests; got != want {
+				t.Errorf("Incorrect number of requests (got %d, want %d)", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// A retryPolicy determines if and when failed operations are retried.
+	retryPolicy struct {
+		// attempts is the maximum number of attempts, including the first.
+		attempts int
+
+		// backoff is the delay before the first retry, which is doubled for
+		// every subsequent retry.
+		backoff time.Duration
+
+		// maxWait is the longest delay before a retry. If the server asks to
+		// wait longer the operation is not retried.
+		maxWait time.Duration
+
+		// sleep is used to wait before retrying.
+		sleep func(time.Duration)
+	}
+
+	// retryTransport is an [http.RoundTripper] that retries requests that fail
+	// transiently, including requests that are rate limited.
+	retryTransport struct {
+		base   http.RoundTripper
+		policy *retryPolicy
+	}
+)
+
+var (
+	retries = retryPolicy{
+		attempts: 4,
+		backoff:  time.Second,
+		maxWait:  time.Minute,
+		sleep:    time.Sleep,
+	}
+
+	// httpClient is the HTTP client shared by all requests to forges.
+	httpClient = &http.Client{
+		Transport: &retryTransport{
+			base:   http.DefaultTransport,
+			policy: &retries,
+		},
+	}
+
+	// transientErrors are (parts of) messages of errors from go-git and the git
+	// binary that indicate a failure that may not occur when retrying.
+	transientErrors = []string{
+		"connection refused",
+		"connection reset",
+		"connection timed out",
+		"could not resolve host",
+		"early eof",
+		"i/o timeout",
+		"no such host",
+		"rpc failed",
+		"returned error: 429",
+		"returned error: 5",
+		"status code: 429",
+		"status code: 5",
+		"tls handshake timeout",
+		"unexpected eof",
+	}
+)
+
+// do runs the given operation until it succeeds, fails with an error that is
+// not transient, or the maximum number of attempts is reached.
+func (p *retryPolicy) do(operation func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil || attempt >= p.attempts || !transient(err) {
+			return err
+		}
+
+		delay := p.delay(attempt)
+		slog.Debug("retrying after transient error", "attempt", attempt, "delay", delay, logKeyErr, err)
+		p.sleep(delay)
+	}
+}
+
+// delay returns the exponential backoff before the given retry attempt.
+func (p *retryPolicy) delay(attempt int) time.Duration {
+	return min(p.backoff<<(attempt-1), p.maxWait)
+}
+
+// wait returns how long to wait before retrying the request with the given
+// response, and whether it should be retried at all.
+func (p *retryPolicy) wait(resp *http.Response, backoff time.Duration) (time.Duration, bool) {
+	after, hasAfter := retryAfter(resp.Header.Get("Retry-After"))
+	limited := rateLimited(resp)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode >= http.StatusInternalServerError:
+	case resp.StatusCode == http.StatusForbidden && (limited || hasAfter):
+	default:
+		return 0, false
+	}
+
+	wait := backoff
+	if hasAfter {
+		wait = after
+	} else if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); limited && err == nil {
+		wait = time.Until(time.Unix(reset, 0))
+	}
+
+	return max(wait, 0), wait <= p.maxWait
+}
+
+// RoundTrip performs the given request, retrying if it fails transiently. The
+// delay before a retry honors the Retry-After and X-RateLimit-Reset headers.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			err = fmt.Errorf("attempt %d: %w", attempt, err)
+		}
+
+		if attempt >= t.policy.attempts || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait, retry := t.policy.delay(attempt), err != nil && transient(err)
+		if resp != nil {
+			wait, retry = t.policy.wait(resp, wait)
+		}
+
+		if !retry || !rewindable(req) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		t.policy.sleep(wait)
+
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, fmt.Errorf("could not rewind request body: %w", bodyErr)
+			}
+
+			// a RoundTripper must not modify the request of the caller
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a number
+// of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+
+	return 0, false
+}
+
+// rateLimited reports whether the given response indicates that the rate limit
+// of the API is exceeded.
+func rateLimited(resp *http.Response) bool {
+	return resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func transient(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, part := range transientErrors {
+		if strings.Contains(msg, part) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,447 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		responses []func(w http.ResponseWriter)
+		status    int
+		requests  int32
+		waits     []time.Duration
+	}
+
+	status := func(code int) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			w.WriteHeader(code)
+		}
+	}
+
+	withHeader := func(code int, key, value string) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			w.Header().Set(key, value)
+			w.WriteHeader(code)
+		}
+	}
+
+	testCases := map[string]TestCase{
+		"success": {
+			responses: []func(w http.ResponseWriter){status(http.StatusOK)},
+			status:    http.StatusOK,
+			requests:  1,
+			waits:     nil,
+		},
+		"server errors": {
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusServiceUnavailable),
+				status(http.StatusBadGateway),
+				status(http.StatusOK),
+			},
+			status:   http.StatusOK,
+			requests: 3,
+			waits:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		"too many attempts": {
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusInternalServerError),
+				status(http.StatusInternalServerError),
+				status(http.StatusInternalServerError),
+				status(http.StatusInternalServerError),
+				status(http.StatusOK),
+			},
+			status:   http.StatusInternalServerError,
+			requests: 4,
+			waits:    []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		"not found": {
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusNotFound),
+				status(http.StatusOK),
+			},
+			status:   http.StatusNotFound,
+			requests: 1,
+			waits:    nil,
+		},
+		"forbidden": {
+			responses: []func(w http.ResponseWriter){
+				status(http.StatusForbidden),
+				status(http.StatusOK),
+			},
+			status:   http.StatusForbidden,
+			requests: 1,
+			waits:    nil,
+		},
+		"Retry-After in seconds": {
+			responses: []func(w http.ResponseWriter){
+				withHeader(http.StatusTooManyRequests, "Retry-After", "7"),
+				status(http.StatusOK),
+			},
+			status:   http.StatusOK,
+			requests: 2,
+			waits:    []time.Duration{7 * time.Second},
+		},
+		"Retry-After on forbidden": {
+			responses: []func(w http.ResponseWriter){
+				withHeader(http.StatusForbidden, "Retry-After", "3"),
+				status(http.StatusOK),
+			},
+			status:   http.StatusOK,
+			requests: 2,
+			waits:    []time.Duration{3 * time.Second},
+		},
+		"Retry-After too long": {
+			responses: []func(w http.ResponseWriter){
+				withHeader(http.StatusTooManyRequests, "Retry-After", "3600"),
+				status(http.StatusOK),
+			},
+			status:   http.StatusTooManyRequests,
+			requests: 1,
+			waits:    nil,
+		},
+		"rate limit reset in the past": {
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", "1")
+					w.WriteHeader(http.StatusForbidden)
+				},
+				status(http.StatusOK),
+			},
+			status:   http.StatusOK,
+			requests: 2,
+			waits:    []time.Duration{0},
+		},
+		"rate limit reset too late": {
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					reset := time.Now().Add(time.Hour).Unix()
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+					w.WriteHeader(http.StatusForbidden)
+				},
+				status(http.StatusOK),
+			},
+			status:   http.StatusForbidden,
+			requests: 1,
+			waits:    nil,
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := requests.Add(1) - 1
+				tt.responses[i](w)
+			}))
+			t.Cleanup(server.Close)
+
+			policy, waits := testPolicy()
+			client := http.Client{
+				Transport: &retryTransport{
+					base:   server.Client().Transport,
+					policy: policy,
+				},
+			}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			_ = resp.Body.Close()
+
+			if got, want := resp.StatusCode, tt.status; got != want {
+				t.Errorf("Incorrect status (got %d, want %d)", got, want)
+			}
+
+			if got, want := requests.Load(), tt.requests; got != want {
+				t.Errorf("Incorrect number of requests (got %d, want %d)", got, want)
+			}
+
+			if got, want := *waits, tt.waits; !slices.Equal(got, want) {
+				t.Errorf("Incorrect waits (got %v, want %v)", got, want)
+			}
+		})
+	}
+
+	t.Run("rate limit reset", func(t *testing.T) {
+		t.Parallel()
+
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				reset := time.Now().Add(30 * time.Second).Unix()
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+				w.WriteHeader(http.StatusForbidden)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		policy, waits := testPolicy()
+		client := http.Client{
+			Transport: &retryTransport{
+				base:   server.Client().Transport,
+				policy: policy,
+			},
+		}
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		_ = resp.Body.Close()
+
+		if got, want := resp.StatusCode, http.StatusOK; got != want {
+			t.Errorf("Incorrect status (got %d, want %d)", got, want)
+		}
+
+		if len(*waits) != 1 || (*waits)[0] < 28*time.Second || (*waits)[0] > 30*time.Second {
+			t.Errorf("Incorrect waits (got %v, want about 30s)", *waits)
+		}
+	})
+
+	t.Run("network error", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		policy, waits := testPolicy()
+		client := http.Client{
+			Transport: &retryTransport{
+				base:   http.DefaultTransport,
+				policy: policy,
+			},
+		}
+
+		if _, err := client.Get(server.URL); err == nil {
+			t.Fatal("Unexpected success")
+		}
+
+		if got, want := len(*waits), 3; got != want {
+			t.Errorf("Incorrect number of retries (got %d, want %d)", got, want)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		policy, waits := testPolicy()
+		client := http.Client{
+			Transport: &retryTransport{
+				base:   server.Client().Transport,
+				policy: policy,
+			},
+		}
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+			t.Errorf("Incorrect error (got %v, want %v)", err, context.Canceled)
+		}
+
+		if got, want := len(*waits), 0; got != want {
+			t.Errorf("Incorrect number of retries (got %d, want %d)", got, want)
+		}
+	})
+
+	t.Run("request body", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			bodies []string
+			mu     sync.Mutex
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		policy, _ := testPolicy()
+		transport := retryTransport{
+			base:   server.Client().Transport,
+			policy: policy,
+		}
+
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, strings.NewReader("query"))
+		body := req.Body
+
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		_ = resp.Body.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if got, want := bodies, []string{"query", "query"}; !slices.Equal(got, want) {
+			t.Errorf("Incorrect request bodies (got %q, want %q)", got, want)
+		}
+
+		if req.Body != body {
+			t.Error("Unexpected modification of the request body")
+		}
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		t.Parallel()
+
+		policy, waits := testPolicy()
+		client := http.Client{
+			Transport: &retryTransport{
+				base:   http.DefaultTransport,
+				policy: policy,
+			},
+		}
+
+		if _, err := client.Get("file:///api/repos"); err == nil {
+			t.Fatal("Unexpected success")
+		}
+
+		if got, want := len(*waits), 0; got != want {
+			t.Errorf("Incorrect number of retries (got %d, want %d)", got, want)
+		}
+	})
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		errs     []error
+		attempts int
+		fails    bool
+	}
+
+	testCases := map[string]TestCase{
+		"success": {
+			errs:     []error{nil},
+			attempts: 1,
+			fails:    false,
+		},
+		"transient error": {
+			errs: []error{
+				errors.New("dial tcp: lookup github.com: no such host"),
+				errors.New("unexpected requesting \"https://github.com\" status code: 502"),
+				nil,
+			},
+			attempts: 3,
+			fails:    false,
+		},
+		"transient git error": {
+			errs: []error{
+				errors.New("fatal: unable to access 'https://github.com/': Could not resolve host: github.com"),
+				nil,
+			},
+			attempts: 2,
+			fails:    false,
+		},
+		"permanent error": {
+			errs: []error{
+				errors.New("couldn't find remote ref refs/tags/v1"),
+				nil,
+			},
+			attempts: 1,
+			fails:    true,
+		},
+		"too many attempts": {
+			errs: []error{
+				errors.New("connection reset by peer"),
+				errors.New("connection reset by peer"),
+				errors.New("connection reset by peer"),
+				errors.New("connection reset by peer"),
+				nil,
+			},
+			attempts: 4,
+			fails:    true,
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, waits := testPolicy()
+
+			attempts := 0
+			err := policy.do(func() error {
+				attempts++
+				return tt.errs[attempts-1]
+			})
+
+			if got, want := err != nil, tt.fails; got != want {
+				t.Errorf("Incorrect failure (got %t, want %t): %v", got, want, err)
+			}
+
+			if got, want := attempts, tt.attempts; got != want {
+				t.Errorf("Incorrect number of attempts (got %d, want %d)", got, want)
+			}
+
+			if got, want := len(*waits), tt.attempts-1; got != want {
+				t.Errorf("Incorrect number of waits (got %d, want %d)", got, want)
+			}
+		})
+	}
+}
+
+// testPolicy returns the default retry policy that records how long it waits
+// instead of sleeping.
+func testPolicy() (*retryPolicy, *[]time.Duration) {
+	waits := make([]time.Duration, 0)
+
+	policy := retries
+	policy.sleep = func(d time.Duration) {
+		waits = append(waits, d)
+	}
+
+	return &policy, &waits
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import "strings"
+
+// Rewrites are URL rewrite rules in the style of the url.<base>.insteadOf
+// configuration of git. It maps URL prefixes to the prefix to use instead.
+//
+// If multiple prefixes match a URL the longest one is used, so rules for a
+// specific owner (e.g. "https://github.com/owner/") take precedence over rules
+// for the whole host (e.g. "https://github.com/").
+type Rewrites map[string]string
+
+// apply returns the given URL rewritten using the longest matching prefix, or
+// the URL itself if no prefix matches.
+func (r Rewrites) apply(url string) string {
+	var match string
+	for prefix := range r {
+		if strings.HasPrefix(url, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+
+	if match == "" {
+		return url
+	}
+
+	return r[match] + strings.TrimPrefix(url, match)
+}
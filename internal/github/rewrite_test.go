@@ -0,0 +1,57 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"testing"
+)
+
+func TestRewritesApply(t *testing.T) {
+	t.Parallel()
+
+	rewrites := Rewrites{
+		"https://github.com/":       "https://git-mirror.example.com/github/",
+		"https://github.com/owner/": "https://owner.example.com/",
+		"https://api.github.com":    "https://git-mirror.example.com/api",
+	}
+
+	testCases := map[string]string{
+		"https://github.com/actions/checkout":   "https://git-mirror.example.com/github/actions/checkout",
+		"https://github.com/owner/project":      "https://owner.example.com/project",
+		"https://github.com/owner-2/project":    "https://git-mirror.example.com/github/owner-2/project",
+		"https://api.github.com/repos/a/b":      "https://git-mirror.example.com/api/repos/a/b",
+		"https://code.forgejo.org/actions/test": "https://code.forgejo.org/actions/test",
+		"ssh://git@github.com/actions/checkout": "ssh://git@github.com/actions/checkout",
+	}
+
+	for in, want := range testCases {
+		t.Run(in, func(t *testing.T) {
+			t.Parallel()
+
+			if got := rewrites.apply(in); got != want {
+				t.Errorf("Incorrect result (got %q, want %q)", got, want)
+			}
+		})
+	}
+
+	t.Run("no rewrites", func(t *testing.T) {
+		t.Parallel()
+
+		var none Rewrites
+		if got, want := none.apply("https://github.com/a/b"), "https://github.com/a/b"; got != want {
+			t.Errorf("Incorrect result (got %q, want %q)", got, want)
+		}
+	})
+}
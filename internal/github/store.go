@@ -0,0 +1,233 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	// Store is a persistent store of repository metadata in a file, such that
+	// the metadata is available offline and need not be looked up every time.
+	Store struct {
+		// Path is the path to the file of the store.
+		Path string
+
+		// TTL is how long stored metadata is used before it is looked up again.
+		// If this has the zero value [DefaultTTL] is used.
+		TTL time.Duration
+	}
+
+	// A Record is the metadata of a repository as kept in a [Store].
+	Record struct {
+		Metadata
+
+		// Fetched is the time at which the metadata was looked up.
+		Fetched time.Time
+
+		// api is the API from which the metadata was looked up, if not derived
+		// from the host.
+		api string
+	}
+
+	storedRecord struct {
+		Host          string    `json:"host"`
+		API           string    `json:"api,omitempty"`
+		Owner         string    `json:"owner"`
+		Project       string    `json:"project"`
+		Archived      bool      `json:"archived"`
+		DefaultBranch string    `json:"default_branch,omitempty"`
+		Visibility    string    `json:"visibility,omitempty"`
+		RenamedTo     string    `json:"renamed_to,omitempty"`
+		Fetched       time.Time `json:"fetched"`
+	}
+
+	storedRecords struct {
+		Repositories []storedRecord `json:"repositories"`
+	}
+)
+
+// DefaultTTL is the default time-to-live of metadata in a [Store].
+const DefaultTTL = 24 * time.Hour
+
+// Lookup returns the metadata of the given repositories. Metadata that is not
+// in the store, or is older than the TTL, is looked up (see [LookupMetadata])
+// and stored, unless offline is set.
+//
+// If metadata cannot be looked up the stored metadata is returned regardless of
+// its age. Repositories for which no metadata is available are omitted. If the
+// store cannot be read it is replaced. If the store cannot be written the
+// metadata is returned together with the error.
+func (s *Store) Lookup(repos []Repository, offline bool) (map[RepoID]Record, error) {
+	records, err := s.load()
+	if err != nil {
+		// start over, discarding the unusable store
+		records = make(map[RepoID]Record)
+	}
+
+	if offline {
+		return pick(records, repos), nil
+	}
+
+	ttl := s.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+
+	stale := slices.DeleteFunc(slices.Clone(repos), func(repo Repository) bool {
+		record, ok := records[repo.ID()]
+		return ok && time.Since(record.Fetched) < ttl
+	})
+
+	if len(stale) > 0 {
+		s.update(records, stale)
+		if err := s.save(records); err != nil {
+			return pick(records, repos), err
+		}
+	}
+
+	return pick(records, repos), nil
+}
+
+// Refresh looks up the metadata of all repositories in the store again,
+// regardless of its age, and returns the number of repositories refreshed.
+func (s *Store) Refresh() (int, error) {
+	records, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	repos := make([]Repository, 0, len(records))
+	for id := range records {
+		repos = append(repos, Repository{
+			Host:     id.Host,
+			API:      records[id].api,
+			Instance: id.Host,
+			Owner:    id.Owner,
+			Project:  id.Project,
+		})
+	}
+
+	refreshed := s.update(records, repos)
+	if err := s.save(records); err != nil {
+		return 0, err
+	}
+
+	return refreshed, nil
+}
+
+func (s *Store) update(records map[RepoID]Record, repos []Repository) int {
+	now := time.Now()
+	found := LookupMetadata(repos)
+	for _, repo := range repos {
+		if metadata, ok := found[repo.ID()]; ok {
+			records[repo.ID()] = Record{Metadata: metadata, Fetched: now, api: repo.API}
+		}
+	}
+
+	return len(found)
+}
+
+func (s *Store) load() (map[RepoID]Record, error) {
+	records := make(map[RepoID]Record)
+
+	raw, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return records, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read metadata store: %w", err)
+	}
+
+	var stored storedRecords
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, fmt.Errorf("could not parse metadata store: %w", err)
+	}
+
+	for _, record := range stored.Repositories {
+		id := RepoID{Host: record.Host, Owner: record.Owner, Project: record.Project}
+		records[id] = Record{
+			Metadata: Metadata{
+				Archived:      record.Archived,
+				DefaultBranch: record.DefaultBranch,
+				Visibility:    record.Visibility,
+				RenamedTo:     record.RenamedTo,
+			},
+			Fetched: record.Fetched,
+			api:     record.API,
+		}
+	}
+
+	return records, nil
+}
+
+func (s *Store) save(records map[RepoID]Record) error {
+	stored := storedRecords{Repositories: make([]storedRecord, 0, len(records))}
+	for id, record := range records {
+		stored.Repositories = append(stored.Repositories, storedRecord{
+			Host:          id.Host,
+			API:           record.api,
+			Owner:         id.Owner,
+			Project:       id.Project,
+			Archived:      record.Archived,
+			DefaultBranch: record.DefaultBranch,
+			Visibility:    record.Visibility,
+			RenamedTo:     record.RenamedTo,
+			Fetched:       record.Fetched.UTC(),
+		})
+	}
+
+	slices.SortFunc(stored.Repositories, func(a, b storedRecord) int {
+		return strings.Compare(a.Host+"/"+a.Owner+"/"+a.Project, b.Host+"/"+b.Owner+"/"+b.Project)
+	})
+
+	raw, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode metadata store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("could not create metadata store: %w", err)
+	}
+
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(raw, '\n'), 0o600); err != nil {
+		return fmt.Errorf("could not write metadata store: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.Path); err != nil {
+		return fmt.Errorf("could not replace metadata store: %w", err)
+	}
+
+	return nil
+}
+
+func pick(records map[RepoID]Record, repos []Repository) map[RepoID]Record {
+	picked := make(map[RepoID]Record, len(repos))
+	for _, repo := range repos {
+		if record, ok := records[repo.ID()]; ok {
+			picked[repo.ID()] = record
+		}
+	}
+
+	return picked
+}
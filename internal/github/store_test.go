@@ -0,0 +1,253 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	stored := Record{Metadata: Metadata{Archived: false}, Fetched: time.Now().Add(-time.Hour)}
+	stale := Record{Metadata: Metadata{Archived: false}, Fetched: time.Now().Add(-48 * time.Hour)}
+
+	setup := func(t *testing.T) (*Store, []Repository, *atomic.Int32) {
+		t.Helper()
+
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			name := strings.TrimPrefix(r.URL.Path, "/repos/")
+			if name == "owner/missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(apiRepoMetadata{
+				Archived:      true,
+				DefaultBranch: "main",
+				FullName:      name,
+				Visibility:    "public",
+			})
+		}))
+		t.Cleanup(server.Close)
+
+		repos := []Repository{
+			{API: server.URL, Owner: "owner", Project: "stored", Ref: "v1"},
+			{API: server.URL, Owner: "owner", Project: "stale", Ref: "v1"},
+			{API: server.URL, Owner: "owner", Project: "new", Ref: "v1"},
+		}
+
+		store := Store{Path: filepath.Join(t.TempDir(), "metadata.json")}
+		records := map[RepoID]Record{
+			repos[0].ID(): stored,
+			repos[1].ID(): stale,
+		}
+
+		if err := store.save(records); err != nil {
+			t.Fatalf("Could not initialize store: %v", err)
+		}
+
+		return &store, repos, &requests
+	}
+
+	t.Run("online", func(t *testing.T) {
+		t.Parallel()
+
+		store, repos, requests := setup(t)
+
+		got, err := store.Lookup(repos, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got, want := requests.Load(), int32(2); got != want {
+			t.Errorf("Incorrect number of requests (got %d, want %d)", got, want)
+		}
+
+		if record := got[repos[0].ID()]; record.Archived {
+			t.Error("Unexpected lookup of fresh metadata")
+		}
+
+		for _, repo := range repos[1:] {
+			record := got[repo.ID()]
+			if !record.Archived || record.DefaultBranch != "main" || record.Visibility != "public" {
+				t.Errorf("Incorrect metadata for %s/%s: %+v", repo.Owner, repo.Project, record)
+			}
+
+			if time.Since(record.Fetched) > time.Minute {
+				t.Errorf("Incorrect fetch time for %s/%s: %v", repo.Owner, repo.Project, record.Fetched)
+			}
+		}
+
+		reloaded, err := store.load()
+		if err != nil {
+			t.Fatalf("Could not reload store: %v", err)
+		}
+
+		if got, want := len(reloaded), 3; got != want {
+			t.Errorf("Incorrect number of stored records (got %d, want %d)", got, want)
+		}
+
+		if got, want := reloaded[repos[2].ID()].api, repos[2].API; got != want {
+			t.Errorf("Incorrect stored API (got %q, want %q)", got, want)
+		}
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		t.Parallel()
+
+		store, repos, requests := setup(t)
+
+		got, err := store.Lookup(repos, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got, want := requests.Load(), int32(0); got != want {
+			t.Errorf("Incorrect number of requests (got %d, want %d)", got, want)
+		}
+
+		if got, want := len(got), 2; got != want {
+			t.Errorf("Incorrect number of results (got %d, want %d)", got, want)
+		}
+
+		if got, want := got[repos[1].ID()].Fetched, stale.Fetched; !got.Equal(want) {
+			t.Errorf("Incorrect fetch time (got %v, want %v)", got, want)
+		}
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		t.Parallel()
+
+		store, repos, requests := setup(t)
+		store.TTL = 72 * time.Hour
+
+		if _, err := store.Lookup(repos, false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got, want := requests.Load(), int32(1); got != want {
+			t.Errorf("Incorrect number of requests (got %d, want %d)", got, want)
+		}
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		t.Parallel()
+
+		store, repos, _ := setup(t)
+
+		missing := Repository{API: repos[0].API, Owner: "owner", Project: "missing"}
+		got, err := store.Lookup([]Repository{missing}, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got, want := len(got), 0; got != want {
+			t.Errorf("Incorrect number of results (got %d, want %d)", got, want)
+		}
+	})
+
+	t.Run("unwritable store", func(t *testing.T) {
+		t.Parallel()
+
+		store, repos, _ := setup(t)
+		if err := os.Mkdir(store.Path+".tmp", 0o700); err != nil {
+			t.Fatalf("Could not block the store: %v", err)
+		}
+
+		got, err := store.Lookup(repos, false)
+		if err == nil {
+			t.Error("Unexpected success")
+		}
+
+		if got, want := len(got), 3; got != want {
+			t.Errorf("Incorrect number of results (got %d, want %d)", got, want)
+		}
+	})
+
+	t.Run("unreadable store", func(t *testing.T) {
+		t.Parallel()
+
+		store, repos, _ := setup(t)
+		if err := os.WriteFile(store.Path, []byte("not json"), 0o600); err != nil {
+			t.Fatalf("Could not write store: %v", err)
+		}
+
+		got, err := store.Lookup(repos, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got, want := len(got), 3; got != want {
+			t.Errorf("Incorrect number of results (got %d, want %d)", got, want)
+		}
+
+		if _, err := store.load(); err != nil {
+			t.Errorf("Store not replaced: %v", err)
+		}
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		t.Parallel()
+
+		store, repos, _ := setup(t)
+
+		records, err := store.load()
+		if err != nil {
+			t.Fatalf("Could not load store: %v", err)
+		}
+
+		for id, record := range records {
+			record.api = repos[0].API
+			records[id] = record
+		}
+
+		if err = store.save(records); err != nil {
+			t.Fatalf("Could not save store: %v", err)
+		}
+
+		refreshed, err := store.Refresh()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got, want := refreshed, 2; got != want {
+			t.Errorf("Incorrect number refreshed (got %d, want %d)", got, want)
+		}
+
+		got, err := store.Lookup(repos[:2], true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for id, record := range got {
+			if !record.Archived {
+				t.Errorf("Metadata not refreshed for %v", id)
+			}
+		}
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// peeledSuffix is the suffix of the names of peeled references, which point to
+// the commit an annotated tag points to.
+const peeledSuffix = "^{}"
+
+// Tags lists the tags of the given repository on its remote, like ls-remote,
+// without cloning it. It returns the commit every tag points to, by name. The
+// Ref of the repository is ignored.
+func Tags(repo *Repository) (map[string]string, error) {
+	refs, err := lsRemote(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags(refs), nil
+}
+
+func lsRemote(repo *Repository) ([]*plumbing.Reference, error) {
+	var errs []error
+	for _, remote := range remotes(repo) {
+		var refs []*plumbing.Reference
+		err := retries.do(func() error {
+			var err error
+			refs, err = listRefs(&remote)
+			return err
+		})
+		if err == nil {
+			return refs, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func listRefs(remote *remote) ([]*plumbing.Reference, error) {
+	r := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{remote.url},
+	})
+
+	refs, err := r.List(&git.ListOptions{
+		Auth:          remote.auth,
+		PeelingOption: git.AppendPeeled,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list refs of %q: %w", remote.url, err)
+	}
+
+	return refs, nil
+}
+
+func tags(refs []*plumbing.Reference) map[string]string {
+	tags := make(map[string]string, len(refs))
+	peeled := make(map[string]string, 0)
+	for _, ref := range refs {
+		if !ref.Name().IsTag() {
+			continue
+		}
+
+		name := ref.Name().Short()
+		if tag, ok := strings.CutSuffix(name, peeledSuffix); ok {
+			peeled[tag] = ref.Hash().String()
+		} else {
+			tags[name] = ref.Hash().String()
+		}
+	}
+
+	for tag, commit := range peeled {
+		tags[tag] = commit
+	}
+
+	return tags
+}
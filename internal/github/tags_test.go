@@ -0,0 +1,64 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	bare := filepath.Join(t.TempDir(), testOwner, testProject+".git")
+	commit := newBareRepo(t, bare, testFiles)
+	runGit(t, bare, "tag", "v2", "main")
+	head := runGit(t, bare, "rev-parse", "main")
+
+	repo := Repository{Owner: testOwner, Project: testProject, URL: bare}
+	got, err := Tags(&repo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"v1": commit,
+		"v2": head,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("Incorrect number of tags (got %d, want %d)", len(got), len(want))
+	}
+
+	for tag, want := range want {
+		if got := got[tag]; got != want {
+			t.Errorf("Incorrect commit for %q (got %q, want %q)", tag, got, want)
+		}
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+
+		repo := Repository{Owner: testOwner, Project: testProject, URL: filepath.Join(t.TempDir(), "missing")}
+		if _, err := Tags(&repo); err == nil {
+			t.Error("Unexpected success")
+		}
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Tarball is a [Fetcher] that downloads repositories as a tarball, from the
+// codeload endpoint of GitHub or the REST API of a GitHub Enterprise Server.
+//
+// Note that tarballs omit files marked export-ignore, apply export-subst
+// substitutions, and convert line endings as configured by the repository (see
+// gitattributes(5)), in which case the files differ from those fetched by other
+// fetchers. Hence, it must not be used to compute checksums.
+type Tarball struct {
+	// Client is the HTTP client used to download tarballs. If this has the zero
+	// value a client that retries transient failures is used.
+	Client *http.Client
+
+	// BaseURL is the base URL of the codeload endpoint. If this has the zero
+	// value tarballs are downloaded from codeload.github.com, or through the
+	// REST API if a token is available or the repository is not on GitHub,
+	// subject to the rewrites of the repository.
+	BaseURL string
+}
+
+const codeloadUrl = "https://codeload.github.com"
+
+// Fetch will download the given repository at the exact ref and extract it into
+// the given directory.
+func (t *Tarball) Fetch(dir string, repo *Repository) (Revision, error) {
+	url, secret := t.url(repo)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not create request for %q: %w", url, err)
+	}
+
+	client := t.Client
+	if client == nil {
+		client = httpClient
+	}
+
+	slog.Debug("downloading tarball", logKeyURL, url)
+	resp, err := send(client, req, secret)
+	if errors.Is(err, errStatusNotFound) {
+		return Revision{}, fmt.Errorf("%w: %w", ErrNotFound, err)
+	} else if err != nil {
+		return Revision{}, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	revision, err := extract(dir, resp.Body)
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not extract tarball from %q: %w", url, err)
+	}
+
+	return revision, nil
+}
+
+func (t *Tarball) url(repo *Repository) (url, secret string) {
+	path := fmt.Sprintf("%s/%s/tar.gz/%s", repo.Owner, repo.Project, repo.Ref)
+	if t.BaseURL != "" {
+		return strings.TrimSuffix(t.BaseURL, "/") + "/" + path, ""
+	}
+
+	if secret = apiToken(repo, canonicalApiUrl(repo)); secret != "" || hostOf(repo) != DefaultHost {
+		api := fmt.Sprintf("%s/repos/%s/%s/tarball/%s", apiUrl(repo), repo.Owner, repo.Project, repo.Ref)
+		return api, secret
+	}
+
+	return repo.Rewrites.apply(codeloadUrl + "/" + path), ""
+}
+
+// extract extracts the gzipped tarball into the given directory, stripping the
+// top-level directory of every entry as included by GitHub. It returns the
+// commit recorded in the tarball by git-archive(1), if any, and its date. The
+// date of an annotated tag is not recorded in the tarball.
+func extract(dir string, r io.Reader) (Revision, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Revision{}, fmt.Errorf("could not decompress: %w", err)
+	}
+
+	defer func() { _ = gz.Close() }()
+
+	var revision Revision
+
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return revision, nil
+		} else if err != nil {
+			return Revision{}, fmt.Errorf("could not read entry: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			revision.Commit = header.PAXRecords["comment"]
+			continue
+		}
+
+		// git-archive(1) uses the commit date as the time of every entry
+		if revision.Commit != "" && revision.Date.IsZero() {
+			revision.Date = header.ModTime.UTC()
+		}
+
+		_, name, _ := strings.Cut(header.Name, "/")
+		if name == "" {
+			continue
+		}
+
+		if !filepath.IsLocal(name) {
+			return Revision{}, fmt.Errorf("invalid path %q", header.Name)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = writeDir(target)
+		case tar.TypeReg:
+			err = writeFile(target, archive, header.FileInfo().Mode())
+		case tar.TypeSymlink:
+			err = writeSymlink(target, header.Linkname)
+		}
+
+		if err != nil {
+			return Revision{}, err
+		}
+	}
+}
+
+func writeDir(target string) error {
+	if err := os.MkdirAll(target, 0o700); err != nil {
+		return couldNotCreate(target, err)
+	}
+
+	return nil
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := writeDir(filepath.Dir(target)); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode.Perm()|0o600)
+	if err != nil {
+		return couldNotCreate(target, err)
+	}
+
+	defer func() { _ = file.Close() }()
+	if _, err := io.Copy(file, r); err != nil {
+		return fmt.Errorf("could not write %q: %w", target, err)
+	}
+
+	return nil
+}
+
+func writeSymlink(target, link string) error {
+	if err := writeDir(filepath.Dir(target)); err != nil {
+		return err
+	}
+
+	if err := os.Symlink(link, target); err != nil {
+		return couldNotCreate(target, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+type (
+	layoutIndex struct {
+		Manifests []layoutDescriptor `json:"manifests"`
+	}
+
+	layoutDescriptor struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	}
+)
+
+// Layout is a [Resolver] that resolves image references using an OCI image
+// layout directory.
+//
+// An image is found in the layout if its index contains a manifest annotated
+// with the normalized reference (e.g. "docker.io/library/alpine:3.8") as the
+// "org.opencontainers.image.ref.name".
+type Layout struct {
+	// Dir is the path to the OCI image layout directory.
+	Dir string
+}
+
+const (
+	indexFile         = "index.json"
+	layoutFile        = "oci-layout"
+	layoutVersion     = `{"imageLayoutVersion": "1.0.0"}`
+	refNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// Resolve returns the digest of the image manifest the given reference refers
+// to in the layout.
+func (l *Layout) Resolve(ref *Reference) (string, error) {
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(l.Dir, indexFile))
+	if err != nil {
+		return "", fmt.Errorf("could not read image layout index: %v", err)
+	}
+
+	var index layoutIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return "", fmt.Errorf("could not parse image layout index: %v", err)
+	}
+
+	name := ref.String()
+	for _, manifest := range index.Manifests {
+		if manifest.Annotations[refNameAnnotation] != name {
+			continue
+		}
+
+		if !validDigest(manifest.Digest) {
+			return "", invalidDigest(ref, manifest.Digest)
+		}
+
+		return manifest.Digest, nil
+	}
+
+	return "", notFound(ref)
+}
+
+// Store records in the layout that the given reference refers to the image
+// manifest with the given digest, replacing what was recorded for it before.
+// The layout is created if it does not exist.
+//
+// Only the index of the layout is updated, the manifest itself is not stored.
+func (l *Layout) Store(ref *Reference, digest string) error {
+	if !validDigest(digest) {
+		return invalidDigest(ref, digest)
+	}
+
+	if err := os.MkdirAll(l.Dir, 0o700); err != nil {
+		return fmt.Errorf("could not create image layout: %v", err)
+	}
+
+	layout := filepath.Join(l.Dir, layoutFile)
+	if _, err := os.Stat(layout); errors.Is(err, fs.ErrNotExist) {
+		if err := os.WriteFile(layout, []byte(layoutVersion+"\n"), 0o600); err != nil {
+			return fmt.Errorf("could not write %s: %v", layoutFile, err)
+		}
+	}
+
+	// The index is decoded generically to retain what this package ignores.
+	index := map[string]any{"schemaVersion": 2}
+	file := filepath.Join(l.Dir, indexFile)
+	if data, err := os.ReadFile(file); err == nil {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err = decoder.Decode(&index); err != nil {
+			return fmt.Errorf("invalid image layout index: %v", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not open image layout index: %v", err)
+	}
+
+	name := ref.String()
+	manifests, _ := index["manifests"].([]any)
+	manifests = slices.DeleteFunc(manifests, func(manifest any) bool {
+		descriptor, _ := manifest.(map[string]any)
+		annotations, _ := descriptor["annotations"].(map[string]any)
+		return annotations[refNameAnnotation] == name
+	})
+
+	index["manifests"] = append(manifests, layoutDescriptor{
+		Digest:      digest,
+		Annotations: map[string]string{refNameAnnotation: name},
+	})
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode image layout index: %v", err)
+	}
+
+	if err := os.WriteFile(file+".tmp", append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("could not write image layout index: %v", err)
+	}
+
+	if err := os.Rename(file+".tmp", file); err != nil {
+		return fmt.Errorf("could not replace image layout index: %v", err)
+	}
+
+	return nil
+}
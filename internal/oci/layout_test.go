@@ -0,0 +1,230 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	t.Parallel()
+
+	const (
+		digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		index  = `{
+  "schemaVersion": 2,
+  "manifests": [
+    {
+      "mediaType": "application/vnd.oci.image.index.v1+json",
+      "digest": "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+      "size": 1234,
+      "annotations": {
+        "org.opencontainers.image.ref.name": "docker.io/library/alpine:3.8"
+      }
+    },
+    {
+      "mediaType": "application/vnd.oci.image.index.v1+json",
+      "digest": "sha256:invalid",
+      "size": 1234,
+      "annotations": {
+        "org.opencontainers.image.ref.name": "docker.io/library/alpine:3.9"
+      }
+    }
+  ]
+}`
+	)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), []byte(index), 0o644); err != nil {
+		t.Fatalf("Could not write index: %v", err)
+	}
+
+	layout := Layout{Dir: dir}
+
+	t.Run("Found", func(t *testing.T) {
+		t.Parallel()
+
+		ref, _ := ParseReference("alpine:3.8")
+		got, err := layout.Resolve(&ref)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if want := digest; got != want {
+			t.Errorf("Incorrect digest (got %q, want %q)", got, want)
+		}
+	})
+
+	t.Run("Pinned", func(t *testing.T) {
+		t.Parallel()
+
+		ref, _ := ParseReference("alpine@" + digest)
+		got, err := layout.Resolve(&ref)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if want := digest; got != want {
+			t.Errorf("Incorrect digest (got %q, want %q)", got, want)
+		}
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		t.Parallel()
+
+		ref, _ := ParseReference("alpine:3.7")
+		_, err := layout.Resolve(&ref)
+		if err == nil {
+			t.Fatal("Unexpected success")
+		}
+
+		if got, want := err.Error(), ErrNotFound.Error(); !strings.Contains(got, want) {
+			t.Errorf("Incorrect error (got %q, want %q)", got, want)
+		}
+	})
+
+	t.Run("Invalid digest", func(t *testing.T) {
+		t.Parallel()
+
+		ref, _ := ParseReference("alpine:3.9")
+		_, err := layout.Resolve(&ref)
+		if err == nil {
+			t.Fatal("Unexpected success")
+		}
+
+		if got, want := err.Error(), ErrInvalidDigest.Error(); !strings.Contains(got, want) {
+			t.Errorf("Incorrect error (got %q, want %q)", got, want)
+		}
+	})
+
+	t.Run("No layout", func(t *testing.T) {
+		t.Parallel()
+
+		missing := Layout{Dir: filepath.Join(dir, "missing")}
+
+		ref, _ := ParseReference("alpine:3.8")
+		if _, err := missing.Resolve(&ref); err == nil {
+			t.Fatal("Unexpected success")
+		}
+	})
+}
+
+func TestLayoutStore(t *testing.T) {
+	t.Parallel()
+
+	const (
+		digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		other  = "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	)
+
+	t.Run("New layout", func(t *testing.T) {
+		t.Parallel()
+
+		layout := Layout{Dir: filepath.Join(t.TempDir(), "layout")}
+
+		ref, _ := ParseReference("alpine:3.8")
+		if err := layout.Store(&ref, digest); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		got, err := layout.Resolve(&ref)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if want := digest; got != want {
+			t.Errorf("Incorrect digest (got %q, want %q)", got, want)
+		}
+
+		if _, err := os.Stat(filepath.Join(layout.Dir, "oci-layout")); err != nil {
+			t.Errorf("Missing oci-layout file: %v", err)
+		}
+	})
+
+	t.Run("Existing layout", func(t *testing.T) {
+		t.Parallel()
+
+		const index = `{
+  "schemaVersion": 2,
+  "manifests": [
+    {
+      "mediaType": "application/vnd.oci.image.index.v1+json",
+      "digest": "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+      "size": 1234,
+      "annotations": {
+        "org.opencontainers.image.ref.name": "docker.io/library/alpine:3.8"
+      }
+    },
+    {
+      "mediaType": "application/vnd.oci.image.index.v1+json",
+      "digest": "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+      "size": 1234,
+      "annotations": {
+        "org.opencontainers.image.ref.name": "docker.io/library/alpine:3.9"
+      }
+    }
+  ]
+}`
+
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "index.json"), []byte(index), 0o644); err != nil {
+			t.Fatalf("Could not write index: %v", err)
+		}
+
+		layout := Layout{Dir: dir}
+
+		ref, _ := ParseReference("alpine:3.8")
+		if err := layout.Store(&ref, other); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if got, _ := layout.Resolve(&ref); got != other {
+			t.Errorf("Incorrect digest for replaced entry (got %q, want %q)", got, other)
+		}
+
+		kept, _ := ParseReference("alpine:3.9")
+		if got, _ := layout.Resolve(&kept); got != digest {
+			t.Errorf("Incorrect digest for other entry (got %q, want %q)", got, digest)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, "index.json"))
+		if err != nil {
+			t.Fatalf("Could not read index: %v", err)
+		}
+
+		if got, want := strings.Count(string(data), `"size": 1234`), 1; got != want {
+			t.Errorf("Unknown fields not retained (got %d, want %d)", got, want)
+		}
+	})
+
+	t.Run("Invalid digest", func(t *testing.T) {
+		t.Parallel()
+
+		layout := Layout{Dir: t.TempDir()}
+
+		ref, _ := ParseReference("alpine:3.8")
+		err := layout.Store(&ref, "sha256:invalid")
+		if err == nil {
+			t.Fatal("Unexpected success")
+		}
+
+		if got, want := err.Error(), ErrInvalidDigest.Error(); !strings.Contains(got, want) {
+			t.Errorf("Incorrect error (got %q, want %q)", got, want)
+		}
+	})
+}
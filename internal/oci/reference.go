@@ -0,0 +1,128 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// A Reference identifies a specific image in a container registry.
+type Reference struct {
+	// Registry is the host (and optionally port) of the container registry that
+	// houses the image.
+	Registry string
+
+	// Repository is the name of the repository (excluding the registry) that
+	// houses the image.
+	Repository string
+
+	// Tag is the tag of the image, if any.
+	Tag string
+
+	// Digest is the digest of the image, if any.
+	Digest string
+}
+
+const (
+	defaultRegistry = "docker.io"
+	defaultTag      = "latest"
+	officialPrefix  = "library/"
+)
+
+var (
+	digestExpr     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$|^sha512:[a-f0-9]{128}$`)
+	repositoryExpr = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	tagExpr        = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+)
+
+// ParseReference parses an image reference such as "alpine:3.8" or
+// "ghcr.io/owner/image@sha256:...".
+//
+// References are normalized the same way the Docker CLI does, so images without
+// a registry are located on Docker Hub and images without a tag or digest get
+// the tag "latest".
+func ParseReference(s string) (Reference, error) {
+	var ref Reference
+
+	name := s
+	if i := strings.IndexRune(name, '@'); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !validDigest(ref.Digest) {
+			return ref, invalidReference(s)
+		}
+	}
+
+	if i := strings.LastIndexByte(name, ':'); i > strings.LastIndexByte(name, '/') {
+		name, ref.Tag = name[:i], name[i+1:]
+		if !tagExpr.MatchString(ref.Tag) {
+			return ref, invalidReference(s)
+		}
+	} else if ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	ref.Registry = defaultRegistry
+	ref.Repository = name
+	if i := strings.IndexRune(name, '/'); i > 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry = host
+			ref.Repository = name[i+1:]
+		}
+	}
+
+	if ref.Registry == "index.docker.io" {
+		ref.Registry = defaultRegistry
+	}
+
+	if ref.Registry == defaultRegistry && !strings.ContainsRune(ref.Repository, '/') {
+		ref.Repository = officialPrefix + ref.Repository
+	}
+
+	if !repositoryExpr.MatchString(ref.Repository) {
+		return ref, invalidReference(s)
+	}
+
+	return ref, nil
+}
+
+func (r *Reference) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(r.Registry)
+	sb.WriteRune('/')
+	sb.WriteString(r.Repository)
+	if r.Tag != "" {
+		sb.WriteRune(':')
+		sb.WriteString(r.Tag)
+	}
+
+	if r.Digest != "" {
+		sb.WriteRune('@')
+		sb.WriteString(r.Digest)
+	}
+
+	return sb.String()
+}
+
+func invalidReference(s string) error {
+	return fmt.Errorf("%v %q", ErrInvalidReference, s)
+}
+
+func validDigest(digest string) bool {
+	return digestExpr.MatchString(digest)
+}
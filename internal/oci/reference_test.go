@@ -0,0 +1,177 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	t.Run("Valid examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			in   string
+			want Reference
+		}
+
+		testCases := map[string]TestCase{
+			"official image, tag": {
+				in: "alpine:3.8",
+				want: Reference{
+					Registry:   "docker.io",
+					Repository: "library/alpine",
+					Tag:        "3.8",
+				},
+			},
+			"official image, no tag": {
+				in: "alpine",
+				want: Reference{
+					Registry:   "docker.io",
+					Repository: "library/alpine",
+					Tag:        "latest",
+				},
+			},
+			"official image, digest": {
+				in: "alpine@" + digest,
+				want: Reference{
+					Registry:   "docker.io",
+					Repository: "library/alpine",
+					Digest:     digest,
+				},
+			},
+			"official image, tag and digest": {
+				in: "alpine:3.8@" + digest,
+				want: Reference{
+					Registry:   "docker.io",
+					Repository: "library/alpine",
+					Tag:        "3.8",
+					Digest:     digest,
+				},
+			},
+			"Docker Hub image": {
+				in: "foo/bar:v1",
+				want: Reference{
+					Registry:   "docker.io",
+					Repository: "foo/bar",
+					Tag:        "v1",
+				},
+			},
+			"Docker Hub image, explicit registry": {
+				in: "index.docker.io/foo/bar:v1",
+				want: Reference{
+					Registry:   "docker.io",
+					Repository: "foo/bar",
+					Tag:        "v1",
+				},
+			},
+			"other registry": {
+				in: "ghcr.io/owner/image:v2",
+				want: Reference{
+					Registry:   "ghcr.io",
+					Repository: "owner/image",
+					Tag:        "v2",
+				},
+			},
+			"other registry, nested repository": {
+				in: "gcr.io/cloud-builders/gradle",
+				want: Reference{
+					Registry:   "gcr.io",
+					Repository: "cloud-builders/gradle",
+					Tag:        "latest",
+				},
+			},
+			"registry with port": {
+				in: "localhost:5000/foo:v3",
+				want: Reference{
+					Registry:   "localhost:5000",
+					Repository: "foo",
+					Tag:        "v3",
+				},
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := ParseReference(tt.in)
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if want := tt.want; got != want {
+					t.Errorf("Incorrect result (got %+v, want %+v)", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid examples", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]string{
+			"empty":             "",
+			"uppercase":         "ghcr.io/OWNER/IMAGE_NAME",
+			"empty tag":         "alpine:",
+			"invalid tag":       "alpine:-3.8",
+			"empty digest":      "alpine@",
+			"invalid digest":    "alpine@sha256:foobar",
+			"unknown algorithm": "alpine@md5:0123456789abcdef0123456789abcdef",
+			"empty repository":  "ghcr.io/:v1",
+		}
+
+		for name, in := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := ParseReference(in); err == nil {
+					t.Fatal("Unexpected success")
+				}
+			})
+		}
+	})
+}
+
+func TestReferenceString(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"alpine":                    "docker.io/library/alpine:latest",
+		"alpine:3.8":                "docker.io/library/alpine:3.8",
+		"ghcr.io/owner/image:v1":    "ghcr.io/owner/image:v1",
+		"localhost:5000/foo/bar:v2": "localhost:5000/foo/bar:v2",
+		"alpine@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef": "docker.io/library/alpine@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	}
+
+	for in, want := range testCases {
+		t.Run(in, func(t *testing.T) {
+			t.Parallel()
+
+			ref, err := ParseReference(in)
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			if got := ref.String(); got != want {
+				t.Errorf("Incorrect result (got %q, want %q)", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Registry is a [Resolver] that resolves image references using the container
+// registry the image is located in, following the OCI distribution spec.
+//
+// Anonymous token authentication (as used by, for example, Docker Hub and the
+// GitHub Container Registry) is supported.
+type Registry struct {
+	// Client is the HTTP client used to communicate with registries. If this is
+	// nil [http.DefaultClient] is used.
+	Client *http.Client
+}
+
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+const (
+	acceptHeader        = "Accept"
+	authorizationHeader = "Authorization"
+	digestHeader        = "Docker-Content-Digest"
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Resolve returns the digest of the image manifest the given reference refers
+// to in its registry.
+func (r *Registry) Resolve(ref *Reference) (string, error) {
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	host := ref.Registry
+	if host == defaultRegistry {
+		host = "registry-1.docker.io"
+	}
+
+	endpoint := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, ref.Repository, ref.Tag)
+
+	resp, err := r.do(http.MethodHead, endpoint, "")
+	if err != nil {
+		return "", err
+	}
+
+	_ = resp.Body.Close()
+
+	var authorization string
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, tokenErr := r.token(resp.Header.Get("Www-Authenticate"))
+		if tokenErr != nil {
+			return "", fmt.Errorf("could not authenticate for %q: %v", ref, tokenErr)
+		}
+
+		authorization = "Bearer " + token
+		resp, err = r.do(http.MethodHead, endpoint, authorization)
+		if err != nil {
+			return "", err
+		}
+
+		_ = resp.Body.Close()
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", notFound(ref)
+	} else if resp.StatusCode != http.StatusOK {
+		return "", badStatus(http.MethodHead, endpoint, resp.StatusCode)
+	}
+
+	digest := resp.Header.Get(digestHeader)
+	if digest == "" {
+		return r.digest(endpoint, authorization)
+	}
+
+	if !validDigest(digest) {
+		return "", invalidDigest(ref, digest)
+	}
+
+	return digest, nil
+}
+
+func (r *Registry) digest(endpoint, authorization string) (string, error) {
+	resp, err := r.do(http.MethodGet, endpoint, authorization)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", badStatus(http.MethodGet, endpoint, resp.StatusCode)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, resp.Body); err != nil {
+		return "", fmt.Errorf("%s %s response unreadable: %v", http.MethodGet, endpoint, err)
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (r *Registry) do(method, endpoint, authorization string) (*http.Response, error) {
+	req, _ := http.NewRequest(method, endpoint, nil)
+	req.Header.Add(acceptHeader, strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Add(authorizationHeader, authorization)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %v", method, endpoint, err)
+	}
+
+	return resp, nil
+}
+
+func (r *Registry) token(challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication scheme %q", scheme)
+	}
+
+	attributes := parseChallenge(params)
+
+	realm, err := url.Parse(attributes["realm"])
+	if err != nil || realm.Scheme != "https" {
+		return "", fmt.Errorf("invalid realm %q", attributes["realm"])
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := attributes[key]; ok {
+			query.Set(key, value)
+		}
+	}
+
+	realm.RawQuery = query.Encode()
+	endpoint := realm.String()
+
+	resp, err := r.do(http.MethodGet, endpoint, "")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", badStatus(http.MethodGet, endpoint, resp.StatusCode)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("%s %s response malformed: %v", http.MethodGet, endpoint, err)
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	return token.AccessToken, nil
+}
+
+func badStatus(method, endpoint string, status int) error {
+	return fmt.Errorf("%s %s failed with status %d", method, endpoint, status)
+}
+
+func parseChallenge(params string) map[string]string {
+	attributes := make(map[string]string)
+	for params != "" {
+		key, rest, ok := strings.Cut(params, "=")
+		if !ok {
+			break
+		}
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+
+		attributes[strings.TrimSpace(key)] = value
+		params = strings.TrimLeft(rest, ", ")
+	}
+
+	return attributes
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	const (
+		digest   = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		manifest = `{"schemaVersion":2}`
+		token    = "s3cr3t"
+	)
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if got, want := r.URL.Query().Get("scope"), "repository:private:pull"; got != want {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			_, _ = fmt.Fprintf(w, `{"token":%q}`, token)
+		case "/v2/public/manifests/v1":
+			w.Header().Set(digestHeader, digest)
+		case "/v2/private/manifests/v1":
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				challenge := fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:private:pull"`, server.URL)
+				w.Header().Set("Www-Authenticate", challenge)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set(digestHeader, digest)
+		case "/v2/no-header/manifests/v1":
+			_, _ = w.Write([]byte(manifest))
+		case "/v2/invalid/manifests/v1":
+			w.Header().Set(digestHeader, "sha256:invalid")
+		case "/v2/unauthorized/manifests/v1":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/v2/broken/manifests/v1":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	registry := Registry{Client: server.Client()}
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	hash := sha256.Sum256([]byte(manifest))
+	manifestDigest := "sha256:" + hex.EncodeToString(hash[:])
+
+	t.Run("Valid examples", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]string{
+			"public":           digest,
+			"private":          digest,
+			"no-header":        manifestDigest,
+			"pinned@" + digest: digest,
+		}
+
+		for image, want := range testCases {
+			t.Run(image, func(t *testing.T) {
+				t.Parallel()
+
+				if !strings.Contains(image, "@") {
+					image += ":v1"
+				}
+
+				ref, err := ParseReference(host + "/" + image)
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				got, err := registry.Resolve(&ref)
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if got != want {
+					t.Errorf("Incorrect digest (got %q, want %q)", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid examples", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []string{
+			"broken",
+			"invalid",
+			"missing",
+			"unauthorized",
+		}
+
+		for _, image := range testCases {
+			t.Run(image, func(t *testing.T) {
+				t.Parallel()
+
+				ref, err := ParseReference(host + "/" + image + ":v1")
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if _, err := registry.Resolve(&ref); err == nil {
+					t.Fatal("Unexpected success")
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import "fmt"
+
+// A Resolver resolves image references to the digest of the image manifest
+// they refer to.
+type Resolver interface {
+	// Resolve returns the digest of the image manifest the given reference
+	// refers to. If the reference includes a digest it is returned as is.
+	Resolve(ref *Reference) (string, error)
+}
+
+func invalidDigest(ref *Reference, digest string) error {
+	return fmt.Errorf("%v %q for %q", ErrInvalidDigest, digest, ref)
+}
+
+func notFound(ref *Reference) error {
+	return fmt.Errorf("%v: %q", ErrNotFound, ref)
+}
@@ -0,0 +1,21 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package osv provides functionality for matching GitHub Actions against
+// security advisories in an offline copy of the OSV database.
+//
+// The database is a zip archive of advisories in the OSV format (see
+// https://ossf.github.io/osv-schema/), such as the export of the ecosystem at
+// https://osv-vulnerabilities.storage.googleapis.com/GitHub%20Actions/all.zip.
+package osv
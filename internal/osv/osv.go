@@ -0,0 +1,248 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+type (
+	// Database is a collection of advisories for the GitHub Actions ecosystem.
+	Database struct {
+		advisories []Advisory
+	}
+
+	// Advisory is a security advisory in the OSV format.
+	Advisory struct {
+		// ID is the identifier of the advisory, e.g. "GHSA-xxxx-xxxx-xxxx".
+		ID string `json:"id"`
+
+		// Aliases are other identifiers of the advisory, e.g. a CVE ID.
+		Aliases []string `json:"aliases"`
+
+		// Withdrawn is the time the advisory was withdrawn, if it was.
+		Withdrawn string `json:"withdrawn"`
+
+		// Affected are the packages and versions affected by the advisory.
+		Affected []Affected `json:"affected"`
+	}
+
+	// Affected is a package, and the versions of it, affected by an advisory.
+	Affected struct {
+		Package  Package  `json:"package"`
+		Ranges   []Range  `json:"ranges"`
+		Versions []string `json:"versions"`
+	}
+
+	// Package identifies a package in an ecosystem.
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	}
+
+	// Range is a range of affected versions, described by a sequence of events.
+	Range struct {
+		Type   string  `json:"type"`
+		Events []Event `json:"events"`
+	}
+
+	// Event is a version at which a package became affected or stopped being
+	// affected. At most one of the fields is set.
+	Event struct {
+		Introduced   string `json:"introduced"`
+		Fixed        string `json:"fixed"`
+		LastAffected string `json:"last_affected"`
+	}
+)
+
+// Ecosystem is the name of the GitHub Actions ecosystem in the OSV database.
+const Ecosystem = "GitHub Actions"
+
+// Parse parses the given zip archive of advisories. Advisories that are
+// withdrawn or do not affect the GitHub Actions ecosystem are ignored.
+func Parse(raw []byte) (*Database, error) {
+	archive, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("could not open OSV database: %v", err)
+	}
+
+	db := Database{advisories: make([]Advisory, 0)}
+	for _, file := range archive.File {
+		if path.Ext(file.Name) != ".json" {
+			continue
+		}
+
+		advisory, err := parseAdvisory(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if advisory.Withdrawn != "" {
+			continue
+		}
+
+		advisory.Affected = slices.DeleteFunc(advisory.Affected, func(a Affected) bool {
+			return a.Package.Ecosystem != Ecosystem
+		})
+
+		if len(advisory.Affected) > 0 {
+			db.advisories = append(db.advisories, advisory)
+		}
+	}
+
+	return &db, nil
+}
+
+// Lookup returns the advisories that affect the given version of the GitHub
+// Action with the given name, as owner/project or owner/project/path. The
+// version must be a semantic version as returned by [Version].
+func (db *Database) Lookup(name, version string) []Advisory {
+	found := make([]Advisory, 0)
+	for _, advisory := range db.advisories {
+		for _, affected := range advisory.Affected {
+			if strings.EqualFold(affected.Package.Name, name) && affected.affects(version) {
+				found = append(found, advisory)
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// Version returns the semantic version that the given git tag represents, e.g.
+// "1.2.3" for "v1.2.3". It returns false if the tag is not a complete semantic
+// version, such as the major version tag "v1".
+func Version(tag string) (string, bool) {
+	version := "v" + strings.TrimPrefix(tag, "v")
+	if !semver.IsValid(version) || semver.Canonical(version) != version {
+		return "", false
+	}
+
+	return strings.TrimPrefix(version, "v"), true
+}
+
+func parseAdvisory(file *zip.File) (Advisory, error) {
+	var advisory Advisory
+
+	f, err := file.Open()
+	if err != nil {
+		return advisory, fmt.Errorf("could not open %s: %v", file.Name, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		return advisory, fmt.Errorf("could not read %s: %v", file.Name, err)
+	}
+
+	if err := json.Unmarshal(raw, &advisory); err != nil {
+		return advisory, fmt.Errorf("could not parse %s: %v", file.Name, err)
+	}
+
+	return advisory, nil
+}
+
+// affects reports whether the given version is affected, i.e. it is listed
+// explicitly or it is included in any of the ranges.
+func (a *Affected) affects(version string) bool {
+	for _, v := range a.Versions {
+		if compare(v, version) == 0 {
+			return true
+		}
+	}
+
+	for _, r := range a.Ranges {
+		if (r.Type == "SEMVER" || r.Type == "ECOSYSTEM") && r.includes(version) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// includes reports whether the given version is included in the range, using
+// the algorithm from the OSV schema. Ranges with a version that is not a
+// semantic version are not evaluated. Limit events are ignored.
+func (r *Range) includes(version string) bool {
+	events := slices.DeleteFunc(slices.Clone(r.Events), func(e Event) bool {
+		return e.version() == ""
+	})
+
+	for _, event := range events {
+		if _, ok := Version(event.version()); !ok && event.Introduced != "0" {
+			return false
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b Event) int {
+		return compare(a.version(), b.version())
+	})
+
+	affected := false
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" || compare(version, event.Introduced) >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if compare(version, event.Fixed) >= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			if compare(version, event.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+
+	return affected
+}
+
+func (e *Event) version() string {
+	for _, v := range []string{e.Introduced, e.Fixed, e.LastAffected} {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// compare compares two semantic versions, with or without a "v" prefix.
+func compare(a, b string) int {
+	return semver.Compare(canonical(a), canonical(b))
+}
+
+// canonical returns the given version with a "v" prefix, as expected by the
+// semver package, where "0" denotes the lowest version.
+func canonical(version string) string {
+	if version == "0" {
+		return "v0.0.0"
+	}
+
+	return "v" + strings.TrimPrefix(version, "v")
+}
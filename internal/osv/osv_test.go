@@ -0,0 +1,337 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	advisories := map[string]any{
+		"GHSA-action.json": Advisory{
+			ID: "GHSA-action",
+			Affected: []Affected{
+				{Package: Package{Ecosystem: Ecosystem, Name: "owner/project"}},
+			},
+		},
+		"GHSA-npm.json": Advisory{
+			ID: "GHSA-npm",
+			Affected: []Affected{
+				{Package: Package{Ecosystem: "npm", Name: "project"}},
+			},
+		},
+		"GHSA-withdrawn.json": Advisory{
+			ID:        "GHSA-withdrawn",
+			Withdrawn: "2026-01-01T00:00:00Z",
+			Affected: []Affected{
+				{Package: Package{Ecosystem: Ecosystem, Name: "owner/project"}},
+			},
+		},
+		"README.md": "# OSV",
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := Parse(archive(t, advisories))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		ids := make([]string, len(db.advisories))
+		for i, advisory := range db.advisories {
+			ids[i] = advisory.ID
+		}
+
+		if got, want := ids, []string{"GHSA-action"}; !slices.Equal(got, want) {
+			t.Errorf("Incorrect advisories (got %v, want %v)", got, want)
+		}
+	})
+
+	t.Run("invalid advisory", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Parse(archive(t, map[string]any{"GHSA-invalid.json": "{"})); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("not a zip archive", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Parse([]byte("not a zip archive")); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	db := Database{
+		advisories: []Advisory{
+			{
+				ID: "GHSA-fixed",
+				Affected: []Affected{{
+					Package: Package{Ecosystem: Ecosystem, Name: "owner/fixed"},
+					Ranges: []Range{{
+						Type: "ECOSYSTEM",
+						Events: []Event{
+							{Introduced: "0"},
+							{Fixed: "1.2.0"},
+						},
+					}},
+				}},
+			},
+			{
+				ID: "GHSA-last-affected",
+				Affected: []Affected{{
+					Package: Package{Ecosystem: Ecosystem, Name: "owner/last-affected"},
+					Ranges: []Range{{
+						Type: "SEMVER",
+						Events: []Event{
+							{Introduced: "2.0.0"},
+							{LastAffected: "2.1.0"},
+						},
+					}},
+				}},
+			},
+			{
+				ID: "GHSA-versions",
+				Affected: []Affected{{
+					Package:  Package{Ecosystem: Ecosystem, Name: "owner/versions/path"},
+					Versions: []string{"v3.0.0"},
+				}},
+			},
+			{
+				ID: "GHSA-git",
+				Affected: []Affected{{
+					Package: Package{Ecosystem: Ecosystem, Name: "owner/git"},
+					Ranges: []Range{{
+						Type: "GIT",
+						Events: []Event{
+							{Introduced: "0"},
+						},
+					}},
+				}},
+			},
+			{
+				ID: "GHSA-invalid",
+				Affected: []Affected{{
+					Package: Package{Ecosystem: Ecosystem, Name: "owner/invalid"},
+					Ranges: []Range{{
+						Type: "ECOSYSTEM",
+						Events: []Event{
+							{Introduced: "0"},
+							{Fixed: "main"},
+						},
+					}},
+				}},
+			},
+		},
+	}
+
+	type TestCase struct {
+		name    string
+		version string
+		want    []string
+	}
+
+	testCases := map[string]TestCase{
+		"before fixed": {
+			name:    "owner/fixed",
+			version: "1.1.9",
+			want:    []string{"GHSA-fixed"},
+		},
+		"fixed": {
+			name:    "owner/fixed",
+			version: "1.2.0",
+			want:    []string{},
+		},
+		"prerelease of fixed": {
+			name:    "owner/fixed",
+			version: "1.2.0-rc.1",
+			want:    []string{"GHSA-fixed"},
+		},
+		"case insensitive": {
+			name:    "Owner/Fixed",
+			version: "1.0.0",
+			want:    []string{"GHSA-fixed"},
+		},
+		"other package": {
+			name:    "owner/other",
+			version: "1.0.0",
+			want:    []string{},
+		},
+		"before introduced": {
+			name:    "owner/last-affected",
+			version: "1.9.9",
+			want:    []string{},
+		},
+		"last affected": {
+			name:    "owner/last-affected",
+			version: "2.1.0",
+			want:    []string{"GHSA-last-affected"},
+		},
+		"after last affected": {
+			name:    "owner/last-affected",
+			version: "2.1.1",
+			want:    []string{},
+		},
+		"listed version": {
+			name:    "owner/versions/path",
+			version: "3.0.0",
+			want:    []string{"GHSA-versions"},
+		},
+		"unlisted version": {
+			name:    "owner/versions/path",
+			version: "3.0.1",
+			want:    []string{},
+		},
+		"git range": {
+			name:    "owner/git",
+			version: "1.0.0",
+			want:    []string{},
+		},
+		"invalid range": {
+			name:    "owner/invalid",
+			version: "1.0.0",
+			want:    []string{},
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			found := db.Lookup(tt.name, tt.version)
+
+			got := make([]string, 0, len(found))
+			for _, advisory := range found {
+				got = append(got, advisory.ID)
+			}
+
+			if want := tt.want; !slices.Equal(got, want) {
+				t.Errorf("Incorrect advisories (got %v, want %v)", got, want)
+			}
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		tag  string
+		want string
+		ok   bool
+	}
+
+	testCases := map[string]TestCase{
+		"full version": {
+			tag:  "v1.2.3",
+			want: "1.2.3",
+			ok:   true,
+		},
+		"without prefix": {
+			tag:  "1.2.3",
+			want: "1.2.3",
+			ok:   true,
+		},
+		"prerelease": {
+			tag:  "v1.2.3-beta.1",
+			want: "1.2.3-beta.1",
+			ok:   true,
+		},
+		"major version": {
+			tag: "v1",
+			ok:  false,
+		},
+		"minor version": {
+			tag: "v1.2",
+			ok:  false,
+		},
+		"build metadata": {
+			tag: "v1.2.3+build",
+			ok:  false,
+		},
+		"zero": {
+			tag: "0",
+			ok:  false,
+		},
+		"branch": {
+			tag: "main",
+			ok:  false,
+		},
+		"commit": {
+			tag: "8f4b7f84864484a7bf31766abe9204da3cbe65b3",
+			ok:  false,
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := Version(tt.tag)
+			if ok != tt.ok {
+				t.Fatalf("Incorrect ok (got %t, want %t)", ok, tt.ok)
+			}
+
+			if want := tt.want; got != want {
+				t.Errorf("Incorrect version (got %q, want %q)", got, want)
+			}
+		})
+	}
+}
+
+func archive(t *testing.T, files map[string]any) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Could not create %s: %v", name, err)
+		}
+
+		raw, ok := content.(string)
+		if !ok {
+			encoded, err := json.Marshal(content)
+			if err != nil {
+				t.Fatalf("Could not encode %s: %v", name, err)
+			}
+
+			raw = string(encoded)
+		}
+
+		if _, err := f.Write([]byte(raw)); err != nil {
+			t.Fatalf("Could not write %s: %v", name, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Could not close archive: %v", err)
+	}
+
+	return buf.Bytes()
+}
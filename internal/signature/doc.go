@@ -0,0 +1,23 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signature provides functionality for creating and verifying detached
+// signatures using SSH keys.
+//
+// Signatures use the OpenSSH signature format (as used by "ssh-keygen -Y") and
+// are verified against an OpenSSH allowed signers file, see ssh-keygen(1).
+//
+// Signatures of git commits and tags, which may also use OpenPGP, can be
+// verified as well.
+package signature
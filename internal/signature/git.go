@@ -0,0 +1,101 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+const (
+	// gitNamespace is the signature namespace used by git for SSH signatures
+	// of commits and tags.
+	gitNamespace = "git"
+
+	pgpKeyEnd   = "-----END PGP PUBLIC KEY BLOCK-----"
+	pgpKeyStart = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	pgpSigStart = "-----BEGIN PGP SIGNATURE-----"
+)
+
+// VerifyGit verifies the signature of a git commit or tag, given the object as
+// it was signed, against the given allowed signers. Both SSH and OpenPGP
+// signatures are supported. Besides the lines of an OpenSSH allowed signers
+// file, the allowed signers may contain ASCII-armored OpenPGP public keys. It
+// returns the identity of the signer if the signature is valid.
+func VerifyGit(payload []byte, signature string, allowedSigners []byte) (string, error) {
+	sshSigners, pgpKeys := splitKeys(allowedSigners)
+	if strings.HasPrefix(strings.TrimSpace(signature), pgpSigStart) {
+		return verifyPGP(payload, signature, pgpKeys)
+	}
+
+	return verify(payload, []byte(signature), sshSigners, gitNamespace)
+}
+
+func verifyPGP(payload []byte, signature string, keys []string) (string, error) {
+	keyring := make(openpgp.EntityList, 0, len(keys))
+	for _, key := range keys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+		if err != nil {
+			return "", fmt.Errorf("invalid allowed OpenPGP key: %v", err)
+		}
+
+		keyring = append(keyring, entities...)
+	}
+
+	if len(keyring) == 0 {
+		return "", errors.New("signed with OpenPGP but there are no allowed OpenPGP keys")
+	}
+
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(payload), strings.NewReader(signature), nil)
+	if err != nil {
+		return "", fmt.Errorf("signature does not match an allowed signer: %v", err)
+	}
+
+	if identity := signer.PrimaryIdentity(); identity != nil {
+		return identity.Name, nil
+	}
+
+	return fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint), nil
+}
+
+// splitKeys splits allowed signers into the OpenSSH allowed signers and the
+// ASCII-armored OpenPGP public keys.
+func splitKeys(allowedSigners []byte) ([]byte, []string) {
+	var (
+		sshSigners bytes.Buffer
+		pgpKeys    = make([]string, 0)
+		pgpKey     strings.Builder
+	)
+
+	for line := range strings.Lines(string(allowedSigners)) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == pgpKeyStart || pgpKey.Len() > 0:
+			pgpKey.WriteString(line)
+			if trimmed == pgpKeyEnd {
+				pgpKeys = append(pgpKeys, pgpKey.String())
+				pgpKey.Reset()
+			}
+		default:
+			sshSigners.WriteString(line)
+		}
+	}
+
+	return sshSigners.Bytes(), pgpKeys
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgparmor "github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+func TestVerifyGit(t *testing.T) {
+	t.Parallel()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate key: %v", err)
+	}
+
+	private, public := mockKey(t, key)
+	pgpEntity, pgpPublic := mockPGPKey(t, "Alice")
+	_, otherPGPPublic := mockPGPKey(t, "Bob")
+
+	payload := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\ncommit\n")
+
+	sshSignature, err := sign(payload, private, gitNamespace)
+	if err != nil {
+		t.Fatalf("Unexpected sign error: %v", err)
+	}
+
+	ghasumSignature, err := Sign(payload, private)
+	if err != nil {
+		t.Fatalf("Unexpected sign error: %v", err)
+	}
+
+	var pgpSignature bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&pgpSignature, pgpEntity, bytes.NewReader(payload), nil); err != nil {
+		t.Fatalf("Unexpected sign error: %v", err)
+	}
+
+	type TestCase struct {
+		signature      string
+		allowedSigners string
+		payload        []byte
+	}
+
+	t.Run("Allowed", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]TestCase{
+			"ssh": {
+				signature:      string(sshSignature),
+				allowedSigners: "alice " + public,
+			},
+			"ssh with openpgp keys": {
+				signature:      string(sshSignature),
+				allowedSigners: otherPGPPublic + "alice " + public + "\n" + pgpPublic,
+			},
+			"ssh git namespace": {
+				signature:      string(sshSignature),
+				allowedSigners: `alice namespaces="git" ` + public,
+			},
+			"openpgp": {
+				signature:      pgpSignature.String(),
+				allowedSigners: pgpPublic,
+			},
+			"openpgp with other keys": {
+				signature:      pgpSignature.String(),
+				allowedSigners: "alice " + public + "\n" + otherPGPPublic + pgpPublic,
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := VerifyGit(payload, tt.signature, []byte(tt.allowedSigners)); err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Not allowed", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]TestCase{
+			"ssh no signers": {
+				signature:      string(sshSignature),
+				allowedSigners: pgpPublic,
+			},
+			"ssh ghasum namespace": {
+				signature:      string(ghasumSignature),
+				allowedSigners: "alice " + public,
+			},
+			"ssh other payload": {
+				signature:      string(sshSignature),
+				allowedSigners: "alice " + public,
+				payload:        []byte("tree\n"),
+			},
+			"openpgp no keys": {
+				signature:      pgpSignature.String(),
+				allowedSigners: "alice " + public,
+			},
+			"openpgp other key": {
+				signature:      pgpSignature.String(),
+				allowedSigners: otherPGPPublic,
+			},
+			"openpgp other payload": {
+				signature:      pgpSignature.String(),
+				allowedSigners: pgpPublic,
+				payload:        []byte("tree\n"),
+			},
+			"openpgp invalid key": {
+				signature:      pgpSignature.String(),
+				allowedSigners: pgpKeyStart + "\n\nfoobar\n" + pgpKeyEnd + "\n",
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				data := payload
+				if tt.payload != nil {
+					data = tt.payload
+				}
+
+				if _, err := VerifyGit(data, tt.signature, []byte(tt.allowedSigners)); err == nil {
+					t.Error("Unexpected success")
+				}
+			})
+		}
+	})
+}
+
+func mockPGPKey(t *testing.T, name string) (*openpgp.Entity, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity(name, "", "", nil)
+	if err != nil {
+		t.Fatalf("Could not generate key: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := pgparmor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("Could not armor key: %v", err)
+	}
+
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("Could not serialize key: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Could not armor key: %v", err)
+	}
+
+	return entity, buf.String() + "\n"
+}
@@ -0,0 +1,341 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type (
+	blob struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}
+
+	signedData struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}
+
+	allowedSigner struct {
+		principals  string
+		key         ssh.PublicKey
+		namespaces  []string
+		validAfter  time.Time
+		validBefore time.Time
+	}
+)
+
+const (
+	// Namespace is the signature namespace used for ghasum signatures.
+	Namespace = "ghasum"
+
+	armorEnd   = "-----END SSH SIGNATURE-----"
+	armorStart = "-----BEGIN SSH SIGNATURE-----"
+	armorWidth = 70
+
+	hashSha256 = "sha256"
+	hashSha512 = "sha512"
+
+	magicPreamble = "SSHSIG"
+	sigVersion    = 1
+)
+
+// Sign creates a detached signature for data using the given (unencrypted)
+// private SSH key in the OpenSSH, PKCS#1, PKCS#8, or SEC 1 format.
+func Sign(data, key []byte) ([]byte, error) {
+	return sign(data, key, Namespace)
+}
+
+// Verify verifies the detached signature for data against the given allowed
+// signers. It returns the principals of the signer if the signature is valid.
+func Verify(data, signature, allowedSigners []byte) (string, error) {
+	return verify(data, signature, allowedSigners, Namespace)
+}
+
+func sign(data, key []byte, namespace string) ([]byte, error) {
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key: %v", err)
+	}
+
+	if signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		algorithmSigner, ok := signer.(ssh.AlgorithmSigner)
+		if !ok {
+			return nil, errors.New("RSA key does not support SHA-2 signatures")
+		}
+
+		signer, err = ssh.NewSignerWithAlgorithms(algorithmSigner, []string{ssh.KeyAlgoRSASHA512})
+		if err != nil {
+			return nil, fmt.Errorf("could not create RSA signer: %v", err)
+		}
+	}
+
+	hash := sha512.Sum512(data)
+	toSign := signedData{
+		Namespace:     namespace,
+		HashAlgorithm: hashSha512,
+		Hash:          hash[:],
+	}
+
+	sig, err := signer.Sign(rand.Reader, append([]byte(magicPreamble), ssh.Marshal(toSign)...))
+	if err != nil {
+		return nil, fmt.Errorf("could not sign: %v", err)
+	}
+
+	b := blob{
+		Version:       sigVersion,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     namespace,
+		HashAlgorithm: hashSha512,
+		Signature:     ssh.Marshal(sig),
+	}
+
+	return armor(append([]byte(magicPreamble), ssh.Marshal(b)...)), nil
+}
+
+func verify(data, signature, allowedSigners []byte, namespace string) (string, error) {
+	raw, err := dearmor(signature)
+	if err != nil {
+		return "", err
+	}
+
+	if !bytes.HasPrefix(raw, []byte(magicPreamble)) {
+		return "", errors.New("malformed signature: missing preamble")
+	}
+
+	var b blob
+	if err = ssh.Unmarshal(raw[len(magicPreamble):], &b); err != nil {
+		return "", fmt.Errorf("malformed signature blob: %v", err)
+	}
+
+	if b.Version != sigVersion {
+		return "", fmt.Errorf("unsupported signature version %d", b.Version)
+	}
+
+	if b.Namespace != namespace {
+		return "", fmt.Errorf("signature namespace %q is not %q", b.Namespace, namespace)
+	}
+
+	var hash []byte
+	switch b.HashAlgorithm {
+	case hashSha256:
+		sum := sha256.Sum256(data)
+		hash = sum[:]
+	case hashSha512:
+		sum := sha512.Sum512(data)
+		hash = sum[:]
+	default:
+		return "", fmt.Errorf("unsupported signature hash algorithm %q", b.HashAlgorithm)
+	}
+
+	key, err := ssh.ParsePublicKey(b.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("malformed signature public key: %v", err)
+	}
+
+	var sig ssh.Signature
+	if err = ssh.Unmarshal(b.Signature, &sig); err != nil {
+		return "", fmt.Errorf("malformed signature value: %v", err)
+	}
+
+	signers, err := parseAllowedSigners(allowedSigners)
+	if err != nil {
+		return "", err
+	}
+
+	signer, err := findSigner(signers, key, namespace, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	signed := signedData{
+		Namespace:     b.Namespace,
+		Reserved:      b.Reserved,
+		HashAlgorithm: b.HashAlgorithm,
+		Hash:          hash,
+	}
+
+	if err := key.Verify(append([]byte(magicPreamble), ssh.Marshal(signed)...), &sig); err != nil {
+		return "", fmt.Errorf("signature does not match: %v", err)
+	}
+
+	return signer.principals, nil
+}
+
+func armor(raw []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(raw)
+
+	var buf bytes.Buffer
+	buf.WriteString(armorStart)
+	buf.WriteRune('\n')
+	for len(encoded) > armorWidth {
+		buf.WriteString(encoded[:armorWidth])
+		buf.WriteRune('\n')
+		encoded = encoded[armorWidth:]
+	}
+
+	buf.WriteString(encoded)
+	buf.WriteRune('\n')
+	buf.WriteString(armorEnd)
+	buf.WriteRune('\n')
+
+	return buf.Bytes()
+}
+
+func dearmor(armored []byte) ([]byte, error) {
+	content := strings.TrimSpace(strings.ReplaceAll(string(armored), "\r\n", "\n"))
+	if !strings.HasPrefix(content, armorStart) || !strings.HasSuffix(content, armorEnd) {
+		return nil, errors.New("malformed signature: missing armor")
+	}
+
+	content = strings.TrimPrefix(content, armorStart)
+	content = strings.TrimSuffix(content, armorEnd)
+	content = strings.ReplaceAll(content, "\n", "")
+
+	raw, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature encoding: %v", err)
+	}
+
+	return raw, nil
+}
+
+func findSigner(signers []allowedSigner, key ssh.PublicKey, namespace string, now time.Time) (*allowedSigner, error) {
+	marshaled := key.Marshal()
+	for _, signer := range signers {
+		if !bytes.Equal(signer.key.Marshal(), marshaled) {
+			continue
+		}
+
+		if len(signer.namespaces) > 0 && !matchesAny(signer.namespaces, namespace) {
+			continue
+		}
+
+		if !signer.validAfter.IsZero() && now.Before(signer.validAfter) {
+			continue
+		}
+
+		if !signer.validBefore.IsZero() && now.After(signer.validBefore) {
+			continue
+		}
+
+		return &signer, nil
+	}
+
+	return nil, fmt.Errorf("signing key %s is not an allowed signer", ssh.FingerprintSHA256(key))
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseAllowedSigners(content []byte) ([]allowedSigner, error) {
+	signers := make([]allowedSigner, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for i := 1; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		principals, rest, _ := strings.Cut(line, " ")
+		key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(rest)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed signer on line %d: %v", i, err)
+		}
+
+		signer := allowedSigner{
+			principals: principals,
+			key:        key,
+		}
+
+		skip := false
+		for _, option := range options {
+			name, value, _ := strings.Cut(option, "=")
+			value = strings.Trim(value, `"`)
+
+			switch strings.ToLower(name) {
+			case "cert-authority":
+				skip = true
+			case "namespaces":
+				signer.namespaces = strings.Split(value, ",")
+			case "valid-after":
+				signer.validAfter, err = parseTime(value)
+			case "valid-before":
+				signer.validBefore, err = parseTime(value)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed signer option on line %d: %v", i, err)
+			}
+		}
+
+		if !skip {
+			signers = append(signers, signer)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read allowed signers: %v", err)
+	}
+
+	return signers, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	location := time.Local
+	if strings.HasSuffix(value, "Z") {
+		location = time.UTC
+		value = strings.TrimSuffix(value, "Z")
+	}
+
+	for _, layout := range []string{"20060102", "200601021504", "20060102150405"} {
+		if len(value) == len(layout) {
+			t, err := time.ParseInLocation(layout, value, location)
+			if err != nil {
+				return t, fmt.Errorf("invalid time %q: %v", value, err)
+			}
+
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
@@ -0,0 +1,286 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		key func(t *testing.T) any
+	}
+
+	testCases := map[string]TestCase{
+		"ed25519": {
+			key: func(t *testing.T) any {
+				_, key, err := ed25519.GenerateKey(rand.Reader)
+				if err != nil {
+					t.Fatalf("Could not generate key: %v", err)
+				}
+
+				return key
+			},
+		},
+		"ecdsa": {
+			key: func(t *testing.T) any {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				if err != nil {
+					t.Fatalf("Could not generate key: %v", err)
+				}
+
+				return key
+			},
+		},
+		"rsa": {
+			key: func(t *testing.T) any {
+				key, err := rsa.GenerateKey(rand.Reader, 2048)
+				if err != nil {
+					t.Fatalf("Could not generate key: %v", err)
+				}
+
+				return key
+			},
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			private, public := mockKey(t, tt.key(t))
+			allowedSigners := "alice@example.com " + public
+			data := []byte("version 1\n\nfoo/bar@v1 checksum\n")
+
+			signature, err := Sign(data, private)
+			if err != nil {
+				t.Fatalf("Unexpected sign error: %v", err)
+			}
+
+			principals, err := Verify(data, signature, []byte(allowedSigners))
+			if err != nil {
+				t.Fatalf("Unexpected verify error: %v", err)
+			}
+
+			if got, want := principals, "alice@example.com"; got != want {
+				t.Errorf("Unexpected principals (got %q, want %q)", got, want)
+			}
+
+			if _, err := Verify([]byte("tampered"), signature, []byte(allowedSigners)); err == nil {
+				t.Error("Unexpected success for tampered data")
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate key: %v", err)
+	}
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate key: %v", err)
+	}
+
+	private, public := mockKey(t, key)
+	_, otherPublic := mockKey(t, otherKey)
+
+	data := []byte("version 1\n")
+	signature, err := Sign(data, private)
+	if err != nil {
+		t.Fatalf("Unexpected sign error: %v", err)
+	}
+
+	t.Run("Allowed", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]string{
+			"only signer":        "alice " + public,
+			"multiple signers":   "bob " + otherPublic + "\nalice " + public,
+			"comments":           "# comment\n\nalice " + public + " comment",
+			"namespace":          `alice namespaces="ghasum" ` + public,
+			"namespaces":         `alice namespaces="git,ghasum" ` + public,
+			"valid after":        `alice valid-after="20000101" ` + public,
+			"valid before":       `alice valid-before="99991231235959Z" ` + public,
+			"windows newlines":   "bob " + otherPublic + "\r\nalice " + public + "\r\n",
+			"wildcard namespace": `alice namespaces="*" ` + public,
+		}
+
+		for name, allowedSigners := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := Verify(data, signature, []byte(allowedSigners)); err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Not allowed", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]string{
+			"no signers":     "",
+			"other signer":   "bob " + otherPublic,
+			"namespace":      `alice namespaces="git" ` + public,
+			"valid after":    `alice valid-after="99991231" ` + public,
+			"valid before":   `alice valid-before="20000101" ` + public,
+			"cert authority": `alice cert-authority ` + public,
+		}
+
+		for name, allowedSigners := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := Verify(data, signature, []byte(allowedSigners)); err == nil {
+					t.Error("Unexpected success")
+				}
+			})
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		t.Parallel()
+
+		allowedSigners := []byte("alice " + public)
+		testCases := map[string][]byte{
+			"empty":            []byte(""),
+			"no armor":         []byte(strings.Split(string(signature), "\n")[1]),
+			"invalid base64":   []byte(armorStart + "\n!!!\n" + armorEnd + "\n"),
+			"invalid preamble": armor([]byte("NOTSIG")),
+			"invalid blob":     armor([]byte(magicPreamble + "foobar")),
+		}
+
+		for name, signature := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := Verify(data, signature, allowedSigners); err == nil {
+					t.Error("Unexpected success")
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid allowed signers", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]string{
+			"no key":       "alice",
+			"invalid key":  "alice ssh-ed25519 foobar",
+			"invalid time": `alice valid-after="tomorrow" ` + public,
+		}
+
+		for name, allowedSigners := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := Verify(data, signature, []byte(allowedSigners)); err == nil {
+					t.Error("Unexpected success")
+				}
+			})
+		}
+	})
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Invalid key", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Sign([]byte("data"), []byte("not a key")); err == nil {
+			t.Error("Unexpected success")
+		}
+	})
+
+	t.Run("Encrypted key", func(t *testing.T) {
+		t.Parallel()
+
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("Could not generate key: %v", err)
+		}
+
+		block, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("passphrase"))
+		if err != nil {
+			t.Fatalf("Could not marshal key: %v", err)
+		}
+
+		if _, err := Sign([]byte("data"), pem.EncodeToMemory(block)); err == nil {
+			t.Error("Unexpected success")
+		}
+	})
+}
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]time.Time{
+		"20250102":        time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local),
+		"202501021504":    time.Date(2025, 1, 2, 15, 4, 0, 0, time.Local),
+		"20250102150405":  time.Date(2025, 1, 2, 15, 4, 5, 0, time.Local),
+		"20250102150405Z": time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+
+	for value, want := range testCases {
+		t.Run(value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseTime(value)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !got.Equal(want) {
+				t.Errorf("Unexpected time (got %v, want %v)", got, want)
+			}
+		})
+	}
+}
+
+func mockKey(t *testing.T, key any) ([]byte, string) {
+	t.Helper()
+
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatalf("Could not marshal private key: %v", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("Could not create signer: %v", err)
+	}
+
+	public := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	return pem.EncodeToMemory(block), public
+}
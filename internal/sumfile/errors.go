@@ -24,6 +24,10 @@ var (
 	// same identifier.
 	ErrDuplicate = errors.New("duplicate entry found")
 
+	// ErrGraph is the error when the dependency graph in a checksum file is
+	// invalid.
+	ErrGraph = errors.New("dependency graph is invalid")
+
 	// ErrHeaders is the error for when sumfile headers are invalid.
 	ErrHeaders = errors.New("sumfile headers are invalid")
 
@@ -0,0 +1,196 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumfile
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// A Conflict represents an entry that was changed in conflicting ways by the
+// two sides of a [Merge].
+type Conflict struct {
+	// ID is the identifier of the conflicting entry.
+	ID []string
+
+	// Ours is the checksum for the entry on our side of the merge. It is empty
+	// if our side does not have the entry.
+	Ours string
+
+	// Theirs is the checksum for the entry on their side of the merge. It is
+	// empty if their side does not have the entry.
+	Theirs string
+}
+
+// Merge performs a three-way merge of two sets of Entries, ours and theirs,
+// that were both derived from the base set of Entries.
+//
+// Entries that were added, changed, or removed on only one side are merged
+// automatically. Entries that were changed differently on both sides are not
+// included in the result but reported as Conflicts instead.
+//
+// The dependency graph is merged in the same way. If the merged dependency
+// graph is not valid, for example due to conflicts, it is omitted from the
+// result and the reason is returned as an error alongside the result. The
+// commit of a merged entry is taken from the side its checksum is taken from,
+// preferring ours.
+func Merge(base, ours, theirs []Entry) ([]Entry, []Conflict, error) {
+	var (
+		baseChecksums   = checksums(base)
+		oursChecksums   = checksums(ours)
+		theirsChecksums = checksums(theirs)
+		oursCommits     = commits(ours)
+		theirsCommits   = commits(theirs)
+	)
+
+	ids := make(map[string][]string, len(ours)+len(theirs))
+	for _, entries := range [][]Entry{base, ours, theirs} {
+		for _, entry := range entries {
+			ids[key(entry.ID)] = entry.ID
+		}
+	}
+
+	merged := make([]Entry, 0, len(ids))
+	conflicts := make([]Conflict, 0)
+	for _, k := range slices.Sorted(maps.Keys(ids)) {
+		var (
+			b = baseChecksums[k]
+			o = oursChecksums[k]
+			t = theirsChecksums[k]
+		)
+
+		var checksum string
+		switch {
+		case o == t:
+			checksum = o
+		case o == b:
+			checksum = t
+		case t == b:
+			checksum = o
+		default:
+			conflicts = append(conflicts, Conflict{ID: ids[k], Ours: o, Theirs: t})
+			continue
+		}
+
+		if checksum == "" {
+			continue
+		}
+
+		commit := oursCommits[k]
+		if checksum != o || (commit == "" && checksum == t) {
+			commit = theirsCommits[k]
+		}
+
+		merged = append(merged, Entry{ID: ids[k], Checksum: checksum, Commit: commit})
+	}
+
+	err := mergeGraph(merged, edges(base), edges(ours), edges(theirs))
+	return merged, conflicts, err
+}
+
+func mergeGraph(merged []Entry, base, ours, theirs map[[2]string]bool) error {
+	all := make(map[[2]string]bool, len(ours)+len(theirs))
+	for _, edges := range []map[[2]string]bool{base, ours, theirs} {
+		maps.Copy(all, edges)
+	}
+
+	index := make(map[string]int, len(merged))
+	for i, entry := range merged {
+		index[key(entry.ID)] = i
+	}
+
+	for _, edge := range slices.SortedFunc(maps.Keys(all), compareEdges) {
+		in := ours[edge]
+		if ours[edge] == base[edge] {
+			in = theirs[edge]
+		}
+
+		if !in {
+			continue
+		}
+
+		child, ok := index[edge[0]]
+		if !ok {
+			continue
+		}
+
+		if edge[1] == rootID {
+			merged[child].Direct = true
+		} else if parent, ok := index[edge[1]]; ok {
+			merged[child].Parents = append(merged[child].Parents, merged[parent].ID)
+		}
+	}
+
+	if len(all) == 0 {
+		return nil
+	}
+
+	err := validGraph(merged)
+	if err != nil {
+		for i := range merged {
+			merged[i].Direct = false
+			merged[i].Parents = nil
+		}
+	}
+
+	return err
+}
+
+func checksums(entries []Entry) map[string]string {
+	m := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		m[key(entry.ID)] = entry.Checksum
+	}
+
+	return m
+}
+
+func commits(entries []Entry) map[string]string {
+	m := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		m[key(entry.ID)] = entry.Commit
+	}
+
+	return m
+}
+
+func compareEdges(a, b [2]string) int {
+	if c := strings.Compare(a[0], b[0]); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a[1], b[1])
+}
+
+func edges(entries []Entry) map[[2]string]bool {
+	m := make(map[[2]string]bool, len(entries))
+	for _, entry := range entries {
+		id := key(entry.ID)
+		if entry.Direct {
+			m[[2]string{id, rootID}] = true
+		}
+
+		for _, parent := range entry.Parents {
+			m[[2]string{id, key(parent)}] = true
+		}
+	}
+
+	return m
+}
+
+func key(id []string) string {
+	return strings.Join(id, "@")
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumfile
+
+import (
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			base      []Entry
+			ours      []Entry
+			theirs    []Entry
+			want      []Entry
+			conflicts []Conflict
+			wantErr   bool
+		}
+
+		var (
+			a1 = Entry{ID: []string{"a", "1"}, Checksum: "foo"}
+			a2 = Entry{ID: []string{"a", "2"}, Checksum: "bar"}
+			b1 = Entry{ID: []string{"b", "1"}, Checksum: "baz"}
+
+			a1Changed = Entry{ID: []string{"a", "1"}, Checksum: "FOO"}
+			a1Other   = Entry{ID: []string{"a", "1"}, Checksum: "oof"}
+
+			a1Direct        = Entry{ID: a1.ID, Checksum: a1.Checksum, Direct: true}
+			a1ChangedDirect = Entry{ID: a1.ID, Checksum: a1Changed.Checksum, Direct: true}
+			a1OtherDirect   = Entry{ID: a1.ID, Checksum: a1Other.Checksum, Direct: true}
+			b1Direct        = Entry{ID: b1.ID, Checksum: b1.Checksum, Direct: true}
+			b1Transitive    = Entry{ID: b1.ID, Checksum: b1.Checksum, Parents: [][]string{a1.ID}}
+			b1Both          = Entry{ID: b1.ID, Checksum: b1.Checksum, Direct: true, Parents: [][]string{a1.ID}}
+
+			a1Commit        = Entry{ID: a1.ID, Checksum: a1.Checksum, Commit: "cafe"}
+			a1OtherCommit   = Entry{ID: a1.ID, Checksum: a1.Checksum, Commit: "f00d"}
+			a1ChangedCommit = Entry{ID: a1.ID, Checksum: a1Changed.Checksum, Commit: "beef"}
+		)
+
+		testCases := map[string]TestCase{
+			"nothing changed": {
+				base:   []Entry{a1, a2},
+				ours:   []Entry{a1, a2},
+				theirs: []Entry{a1, a2},
+				want:   []Entry{a1, a2},
+			},
+			"disjoint additions": {
+				base:   []Entry{a1},
+				ours:   []Entry{a1, a2},
+				theirs: []Entry{a1, b1},
+				want:   []Entry{a1, a2, b1},
+			},
+			"identical additions": {
+				base:   []Entry{},
+				ours:   []Entry{a1},
+				theirs: []Entry{a1},
+				want:   []Entry{a1},
+			},
+			"removal on one side": {
+				base:   []Entry{a1, a2},
+				ours:   []Entry{a1},
+				theirs: []Entry{a1, a2},
+				want:   []Entry{a1},
+			},
+			"removal on both sides": {
+				base:   []Entry{a1, a2},
+				ours:   []Entry{a1},
+				theirs: []Entry{a1},
+				want:   []Entry{a1},
+			},
+			"addition and removal": {
+				base:   []Entry{a1},
+				ours:   []Entry{a1, a2},
+				theirs: []Entry{},
+				want:   []Entry{a2},
+			},
+			"change on one side": {
+				base:   []Entry{a1},
+				ours:   []Entry{a1},
+				theirs: []Entry{a1Changed},
+				want:   []Entry{a1Changed},
+			},
+			"conflicting additions": {
+				base:   []Entry{a2},
+				ours:   []Entry{a1, a2},
+				theirs: []Entry{a1Other, a2},
+				want:   []Entry{a2},
+				conflicts: []Conflict{
+					{ID: a1.ID, Ours: a1.Checksum, Theirs: a1Other.Checksum},
+				},
+			},
+			"conflicting changes": {
+				base:   []Entry{a1},
+				ours:   []Entry{a1Changed},
+				theirs: []Entry{a1Other},
+				want:   []Entry{},
+				conflicts: []Conflict{
+					{ID: a1.ID, Ours: a1Changed.Checksum, Theirs: a1Other.Checksum},
+				},
+			},
+			"change and removal": {
+				base:   []Entry{a1, a2},
+				ours:   []Entry{a1Changed, a2},
+				theirs: []Entry{a2},
+				want:   []Entry{a2},
+				conflicts: []Conflict{
+					{ID: a1.ID, Ours: a1Changed.Checksum, Theirs: ""},
+				},
+			},
+			"commit added on one side": {
+				base:   []Entry{a1},
+				ours:   []Entry{a1},
+				theirs: []Entry{a1Commit},
+				want:   []Entry{a1Commit},
+			},
+			"commit changed on both sides": {
+				base:   []Entry{a1},
+				ours:   []Entry{a1Commit},
+				theirs: []Entry{a1OtherCommit},
+				want:   []Entry{a1Commit},
+			},
+			"commit of changed side": {
+				base:   []Entry{a1Commit},
+				ours:   []Entry{a1Commit},
+				theirs: []Entry{a1ChangedCommit},
+				want:   []Entry{a1ChangedCommit},
+			},
+			"graph, nothing changed": {
+				base:   []Entry{a1Direct, b1Transitive},
+				ours:   []Entry{a1Direct, b1Transitive},
+				theirs: []Entry{a1Direct, b1Transitive},
+				want:   []Entry{a1Direct, b1Transitive},
+			},
+			"graph, edge added on our side": {
+				base:   []Entry{a1Direct, b1Direct},
+				ours:   []Entry{a1Direct, b1Both},
+				theirs: []Entry{a1Direct, b1Direct},
+				want:   []Entry{a1Direct, b1Both},
+			},
+			"graph, edge added on their side": {
+				base:   []Entry{a1Direct, b1Direct},
+				ours:   []Entry{a1Direct, b1Direct},
+				theirs: []Entry{a1Direct, b1Both},
+				want:   []Entry{a1Direct, b1Both},
+			},
+			"graph, edge removed on one side": {
+				base:   []Entry{a1Direct, b1Both},
+				ours:   []Entry{a1Direct, b1Both},
+				theirs: []Entry{a1Direct, b1Direct},
+				want:   []Entry{a1Direct, b1Direct},
+			},
+			"graph, parent removed on one side": {
+				base:   []Entry{a1Direct, b1Transitive},
+				ours:   []Entry{a1Direct, b1Transitive},
+				theirs: []Entry{b1Direct},
+				want:   []Entry{b1Direct},
+			},
+			"graph, added on one side": {
+				base:   []Entry{a1},
+				ours:   []Entry{a1Direct, b1Transitive},
+				theirs: []Entry{a1},
+				want:   []Entry{a1Direct, b1Transitive},
+			},
+			"graph, invalid after merging": {
+				base:   []Entry{a1Direct, b1Transitive},
+				ours:   []Entry{a1ChangedDirect, b1Transitive},
+				theirs: []Entry{a1OtherDirect, b1Transitive},
+				want:   []Entry{b1},
+				conflicts: []Conflict{
+					{ID: a1.ID, Ours: a1Changed.Checksum, Theirs: a1Other.Checksum},
+				},
+				wantErr: true,
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, conflicts, err := Merge(tt.base, tt.ours, tt.theirs)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Unexpected error: %v", err)
+				}
+
+				if !SetEqual(got, tt.want) {
+					t.Errorf("Unexpected result (got %v, want %v)", got, tt.want)
+				}
+
+				if got, want := len(conflicts), len(tt.conflicts); got != want {
+					t.Fatalf("Unexpected number of conflicts (got %d, want %d)", got, want)
+				}
+
+				for i, got := range conflicts {
+					want := tt.conflicts[i]
+					if !slices.Equal(got.ID, want.ID) || got.Ours != want.Ours || got.Theirs != want.Theirs {
+						t.Errorf("Unexpected %dth conflict (got %v, want %v)", i, got, want)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("Arbitrary", func(t *testing.T) {
+		t.Parallel()
+
+		oneSided := func(base, changed []Entry) bool {
+			if validV2(base) != nil || validV2(changed) != nil {
+				return true
+			}
+
+			want := Stored(VersionLatest, changed)
+
+			ours, conflicts, err := Merge(base, changed, base)
+			if len(conflicts) != 0 || err != nil || !SetEqual(Stored(VersionLatest, ours), want) {
+				return false
+			}
+
+			theirs, conflicts, err := Merge(base, base, changed)
+			return len(conflicts) == 0 && err == nil && SetEqual(Stored(VersionLatest, theirs), want)
+		}
+
+		if err := quick.Check(oneSided, nil); err != nil {
+			t.Errorf("One-sided merge did not produce the changed side for: %v", err)
+		}
+	})
+}
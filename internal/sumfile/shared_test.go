@@ -16,6 +16,8 @@ package sumfile
 
 import (
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -45,6 +47,35 @@ OUTER_WANT:
 	return true
 }
 
+// Stored returns the given Entries as they would be stored in a checksum file
+// of the given version.
+func Stored(version Version, entries []Entry) []Entry {
+	stored := make([]Entry, len(entries))
+	for i, entry := range entries {
+		stored[i] = Entry{
+			Checksum: entry.Checksum,
+			ID:       entry.ID,
+		}
+
+		if version >= Version2 {
+			stored[i].Direct = entry.Direct
+			for _, parent := range entry.Parents {
+				stored[i].Parents = append(stored[i].Parents, parent)
+			}
+
+			slices.SortFunc(stored[i].Parents, func(a, b []string) int {
+				return strings.Compare(key(a), key(b))
+			})
+		}
+
+		if version >= Version3 {
+			stored[i].Commit = entry.Commit
+		}
+	}
+
+	return stored
+}
+
 func TestSetEqual(t *testing.T) {
 	t.Parallel()
 
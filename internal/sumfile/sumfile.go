@@ -26,9 +26,25 @@ type Entry struct {
 	// Checksum is the checksum value for the entry.
 	Checksum string
 
+	// Commit is the commit for which the checksum was computed. It is empty if
+	// the commit is not known.
+	//
+	// Only stored in Version3 and later checksum files.
+	Commit string
+
+	// Direct is set if the entry is used directly by the repository.
+	//
+	// Only stored in Version2 and later checksum files.
+	Direct bool
+
 	// ID is the identifier for the entry. Can have any number of parts but must
 	// not be empty.
 	ID []string
+
+	// Parents are the identifiers of the entries that use this entry.
+	//
+	// Only stored in Version2 and later checksum files.
+	Parents [][]string
 }
 
 // Decode parses the given checksum file content into Entries. This will error
@@ -61,6 +77,10 @@ func Encode(version Version, checksums []Entry) (string, error) {
 	switch version {
 	case Version1:
 		encoded, err = encodeV1(checksums)
+	case Version2:
+		encoded, err = encodeV2(checksums)
+	case Version3:
+		encoded, err = encodeV3(checksums)
 	default:
 		err = unknownVersion(version)
 	}
@@ -68,6 +88,18 @@ func Encode(version Version, checksums []Entry) (string, error) {
 	return fmt.Sprintf("version %d\n\n%s", version, encoded), err
 }
 
+// HasGraph reports whether the given Entries include a dependency graph, that
+// is whether any of the Entries is used directly or has parents.
+func HasGraph(entries []Entry) bool {
+	for _, entry := range entries {
+		if entry.Direct || len(entry.Parents) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 func parseFile(stored string) (map[string]string, []Entry, error) {
 	lines := strings.Split(stored, "\n")
 	if strings.Contains(stored, "\r") {
@@ -98,6 +130,10 @@ func parseFile(stored string) (map[string]string, []Entry, error) {
 	switch version {
 	case Version1:
 		entries, err = decodeV1(content)
+	case Version2:
+		entries, err = decodeV2(content)
+	case Version3:
+		entries, err = decodeV3(content)
 	default:
 		err = unknownVersion(version)
 	}
@@ -151,6 +187,10 @@ func extractVersion(headers map[string]string) (Version, error) {
 	return Version(rawVersion), nil
 }
 
+func syntaxError(line int) error {
+	return fmt.Errorf("%v on line %d", ErrSyntax, line)
+}
+
 func unknownVersion(version Version) error {
 	err := fmt.Errorf("unknown version %d", version)
 	return errors.Join(ErrVersion, err)
@@ -36,7 +36,7 @@ func TestAnyVersion(t *testing.T) {
 			return false
 		}
 
-		return SetEqual(decoded, entries)
+		return SetEqual(Stored(version, decoded), Stored(version, entries))
 	}
 
 	if err := quick.Check(decodable, nil); err != nil {
@@ -65,6 +65,26 @@ actions/checkout@v4.2.0 e6ng7MJDyAPkTZ/6d/plZK2YhZRzJZvxhYAPUPpNAzc=
 				},
 			},
 		},
+		"version 2 with graph": {
+			sumfile: `version 2
+
+actions/checkout@v4.2.0 e6ng7MJDyAPkTZ/6d/plZK2YhZRzJZvxhYAPUPpNAzc=
+actions/composite@v1 a3ht0IImDEBC7NqbohfejBtv7W5GdKiGJgc4OtYkjEs=
+
+actions/checkout@v4.2.0 actions/composite@v1
+actions/composite@v1 .
+`,
+			want: []Entry{
+				{
+					Checksum: "e6ng7MJDyAPkTZ/6d/plZK2YhZRzJZvxhYAPUPpNAzc=",
+					ID:       []string{"actions/checkout", "v4.2.0"},
+				},
+				{
+					Checksum: "a3ht0IImDEBC7NqbohfejBtv7W5GdKiGJgc4OtYkjEs=",
+					ID:       []string{"actions/composite", "v1"},
+				},
+			},
+		},
 		"windows newlines": {
 			sumfile: "version 1\r\n\r\nactions/checkout@v4.2.0 e6ng7MJDyAPkTZ/6d/plZK2YhZRzJZvxhYAPUPpNAzc=\r\n",
 			want: []Entry{
@@ -104,6 +124,50 @@ actions/checkout@v4.2.0 e6ng7MJDyAPkTZ/6d/plZK2YhZRzJZvxhYAPUPpNAzc=
 	}
 }
 
+func TestHasGraph(t *testing.T) {
+	t.Parallel()
+
+	type TestCase struct {
+		entries []Entry
+		want    bool
+	}
+
+	testCases := map[string]TestCase{
+		"no entries": {
+			entries: []Entry{},
+			want:    false,
+		},
+		"no graph": {
+			entries: []Entry{
+				{ID: []string{"foo"}, Checksum: "bar"},
+			},
+			want: false,
+		},
+		"direct": {
+			entries: []Entry{
+				{ID: []string{"foo"}, Checksum: "bar", Direct: true},
+			},
+			want: true,
+		},
+		"parents": {
+			entries: []Entry{
+				{ID: []string{"foo"}, Checksum: "bar", Parents: [][]string{{"baz"}}},
+			},
+			want: true,
+		},
+	}
+
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := HasGraph(tt.entries), tt.want; got != want {
+				t.Errorf("Wrong result (got %t, want %t)", got, want)
+			}
+		})
+	}
+}
+
 func TestNoChecksums(t *testing.T) {
 	t.Parallel()
 
@@ -15,6 +15,8 @@
 package sumfile
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -52,3 +54,71 @@ func hasDuplicates(entries []Entry) bool {
 
 	return false
 }
+
+func validGraph(entries []Entry) error {
+	parents := make(map[string][]string, len(entries))
+	for _, entry := range entries {
+		parents[key(entry.ID)] = nil
+	}
+
+	for _, entry := range entries {
+		id := key(entry.ID)
+		if !entry.Direct && len(entry.Parents) == 0 {
+			err := fmt.Errorf("entry %q is not used", id)
+			return errors.Join(ErrGraph, err)
+		}
+
+		seen := make(map[string]struct{}, len(entry.Parents))
+		for _, parent := range entry.Parents {
+			parentID := key(parent)
+			if _, ok := parents[parentID]; !ok {
+				err := fmt.Errorf("entry %q has unknown parent %q", id, parentID)
+				return errors.Join(ErrGraph, err)
+			}
+
+			if _, ok := seen[parentID]; ok {
+				err := fmt.Errorf("entry %q has duplicate parent %q", id, parentID)
+				return errors.Join(ErrGraph, err)
+			}
+
+			seen[parentID] = struct{}{}
+			parents[id] = append(parents[id], parentID)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(parents))
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visiting:
+			err := fmt.Errorf("entry %q depends on itself", id)
+			return errors.Join(ErrGraph, err)
+		case visited:
+			return nil
+		}
+
+		state[id] = visiting
+		for _, parent := range parents[id] {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+
+		state[id] = visited
+		return nil
+	}
+
+	for _, entry := range entries {
+		if err := visit(key(entry.ID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
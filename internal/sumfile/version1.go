@@ -16,18 +16,20 @@ package sumfile
 
 import (
 	"errors"
-	"fmt"
 	"sort"
 	"strings"
 )
 
+// whitespace are the characters that cannot be used in the fields of an entry.
+const whitespace = "\n\r "
+
 func decodeV1(lines []string) ([]Entry, error) {
 	entries := make([]Entry, len(lines))
 	for i, line := range lines {
 		// split "line" into "id[@id..]" "sum"
 		j := strings.IndexRune(line, ' ')
 		if j <= 0 || j >= len(line)-1 {
-			return nil, fmt.Errorf("%v on line %d", ErrSyntax, i+3)
+			return nil, syntaxError(i + 3)
 		}
 
 		entries[i] = Entry{
@@ -48,6 +50,12 @@ func encodeV1(entries []Entry) (string, error) {
 		return "", errors.Join(ErrCorrupted, err)
 	}
 
+	return encodeChecksums(entries, false), nil
+}
+
+// encodeChecksums encodes the checksums of the given entries, one per line and
+// sorted, optionally followed by the commit of each entry if it is known.
+func encodeChecksums(entries []Entry, commits bool) string {
 	var sb strings.Builder
 	lines := make([]string, len(entries))
 	for i, entry := range entries {
@@ -60,6 +68,10 @@ func encodeV1(entries []Entry) (string, error) {
 
 		sb.WriteRune(' ')
 		sb.WriteString(entry.Checksum)
+		if commits && entry.Commit != "" {
+			sb.WriteRune(' ')
+			sb.WriteString(entry.Commit)
+		}
 		sb.WriteRune('\n')
 
 		lines[i] = sb.String()
@@ -67,7 +79,7 @@ func encodeV1(entries []Entry) (string, error) {
 	}
 
 	sort.Strings(lines)
-	return strings.Join(lines, ""), nil
+	return strings.Join(lines, "")
 }
 
 func validV1(entries []Entry) error {
@@ -80,7 +92,7 @@ func validV1(entries []Entry) error {
 	}
 
 	for _, entry := range entries {
-		if strings.ContainsAny(entry.Checksum, "\n\r ") {
+		if strings.ContainsAny(entry.Checksum, whitespace) {
 			return ErrSyntax
 		}
 
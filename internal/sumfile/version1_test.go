@@ -38,7 +38,7 @@ func TestVersion1(t *testing.T) {
 			return true // Ignore errors, tested separately
 		}
 
-		return SetEqual(decoded, entries)
+		return SetEqual(decoded, Stored(Version1, entries))
 	}
 
 	if err := quick.Check(correct, nil); err != nil {
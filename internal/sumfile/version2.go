@@ -0,0 +1,128 @@
+// Copyright 2024 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumfile
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// rootID is the identifier used in the dependency graph for the repository.
+const rootID = "."
+
+func decodeV2(lines []string) ([]Entry, error) {
+	checksums, graph := lines, []string{}
+	if i := slices.Index(lines, ""); i != -1 {
+		checksums, graph = lines[:i], lines[i+1:]
+		if len(graph) == 0 {
+			return nil, syntaxError(len(checksums) + 3)
+		}
+	}
+
+	entries, err := decodeV1(checksums)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		index[key(entry.ID)] = i
+	}
+
+	offset := len(checksums) + 4
+	for i, line := range graph {
+		// split "line" into "id[@id..]" "parent[@parent..]"
+		j := strings.IndexRune(line, ' ')
+		if j <= 0 || j >= len(line)-1 {
+			return nil, syntaxError(offset + i)
+		}
+
+		child, ok := index[line[:j]]
+		if !ok {
+			err := fmt.Errorf("unknown entry %q on line %d", line[:j], offset+i)
+			return nil, errors.Join(ErrCorrupted, ErrGraph, err)
+		}
+
+		if parent := line[j+1:]; parent == rootID {
+			if entries[child].Direct {
+				err := fmt.Errorf("duplicate edge on line %d", offset+i)
+				return nil, errors.Join(ErrCorrupted, ErrGraph, err)
+			}
+
+			entries[child].Direct = true
+		} else {
+			entries[child].Parents = append(entries[child].Parents, strings.Split(parent, "@"))
+		}
+	}
+
+	if err := validV2(entries); err != nil {
+		return nil, errors.Join(ErrCorrupted, err)
+	}
+
+	return entries, nil
+}
+
+func encodeV2(entries []Entry) (string, error) {
+	if err := validV2(entries); err != nil {
+		return "", errors.Join(ErrCorrupted, err)
+	}
+
+	checksums := encodeChecksums(entries, false)
+	if !HasGraph(entries) {
+		return checksums, nil
+	}
+
+	return checksums + "\n" + encodeGraph(entries), nil
+}
+
+// encodeGraph encodes the dependency graph of the given entries, one edge per
+// line and sorted.
+func encodeGraph(entries []Entry) string {
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		id := key(entry.ID)
+		if entry.Direct {
+			lines = append(lines, id+" "+rootID+"\n")
+		}
+
+		for _, parent := range entry.Parents {
+			lines = append(lines, id+" "+key(parent)+"\n")
+		}
+	}
+
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
+
+func validV2(entries []Entry) error {
+	if err := validV1(entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if key(entry.ID) == rootID {
+			return ErrSyntax
+		}
+	}
+
+	if !HasGraph(entries) {
+		return nil
+	}
+
+	return validGraph(entries)
+}
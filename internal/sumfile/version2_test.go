@@ -0,0 +1,419 @@
+// Copyright 2024-2025 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestVersion2(t *testing.T) {
+	t.Parallel()
+
+	correct := func(entries []Entry) bool {
+		if err := validV2(entries); err != nil {
+			return true
+		}
+
+		encoded, _ := encodeV2(entries)
+		lines := strings.Split(encoded, "\n")
+
+		decoded, err := decodeV2(lines[:len(lines)-1])
+		if err != nil {
+			return true // Ignore errors, tested separately
+		}
+
+		return SetEqual(Stored(Version2, decoded), Stored(Version2, entries))
+	}
+
+	if err := quick.Check(correct, nil); err != nil {
+		t.Errorf("decode(encode(x)) != x for: %v", err)
+	}
+
+	decodable := func(entries []Entry) bool {
+		if err := validV2(entries); err != nil {
+			return true
+		}
+
+		encoded, _ := encodeV2(entries)
+		lines := strings.Split(encoded, "\n")
+
+		_, err := decodeV2(lines[:len(lines)-1])
+		return err == nil
+	}
+
+	if err := quick.Check(decodable, nil); err != nil {
+		t.Errorf("decode(encode(x)) errored for: %v", err)
+	}
+
+	deterministic := func(entries []Entry) bool {
+		got1, err1 := encodeV2(entries)
+		got2, err2 := encodeV2(entries)
+		return got1 == got2 && ((err1 == nil) == (err2 == nil))
+	}
+
+	if err := quick.Check(deterministic, nil); err != nil {
+		t.Errorf("encode(x) != encode(x) for: %v", err)
+	}
+}
+
+func TestDecodeV2(t *testing.T) {
+	t.Run("Valid examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			content []string
+			want    []Entry
+		}
+
+		testCases := map[string]TestCase{
+			"no checksums": {
+				content: []string{},
+				want:    []Entry{},
+			},
+			"no graph": {
+				content: []string{
+					"foo@v1 bar",
+				},
+				want: []Entry{
+					{
+						Checksum: "bar",
+						ID:       []string{"foo", "v1"},
+					},
+				},
+			},
+			"direct only": {
+				content: []string{
+					"foo@v1 bar",
+					"",
+					"foo@v1 .",
+				},
+				want: []Entry{
+					{
+						Checksum: "bar",
+						Direct:   true,
+						ID:       []string{"foo", "v1"},
+					},
+				},
+			},
+			"transitive": {
+				content: []string{
+					"foo@v1 bar",
+					"hello@v2 world",
+					"",
+					"foo@v1 .",
+					"hello@v2 foo@v1",
+				},
+				want: []Entry{
+					{
+						Checksum: "bar",
+						Direct:   true,
+						ID:       []string{"foo", "v1"},
+					},
+					{
+						Checksum: "world",
+						ID:       []string{"hello", "v2"},
+						Parents:  [][]string{{"foo", "v1"}},
+					},
+				},
+			},
+			"direct and transitive": {
+				content: []string{
+					"foo@v1 bar",
+					"hello@v2 world",
+					"",
+					"foo@v1 .",
+					"hello@v2 .",
+					"hello@v2 foo@v1",
+				},
+				want: []Entry{
+					{
+						Checksum: "bar",
+						Direct:   true,
+						ID:       []string{"foo", "v1"},
+					},
+					{
+						Checksum: "world",
+						Direct:   true,
+						ID:       []string{"hello", "v2"},
+						Parents:  [][]string{{"foo", "v1"}},
+					},
+				},
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := decodeV2(tt.content)
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if !SetEqual(Stored(Version2, got), Stored(Version2, tt.want)) {
+					t.Errorf("Incorrect result (got %v, want %v)", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			content []string
+			want    string
+		}
+
+		testCases := map[string]TestCase{
+			"syntax error in checksums": {
+				content: []string{
+					"foobar",
+				},
+				want: "line 3",
+			},
+			"empty graph": {
+				content: []string{
+					"foo@v1 bar",
+					"",
+				},
+				want: "line 4",
+			},
+			"syntax error in graph": {
+				content: []string{
+					"foo@v1 bar",
+					"",
+					"foo@v1",
+				},
+				want: "line 5",
+			},
+			"syntax error later in graph": {
+				content: []string{
+					"foo@v1 bar",
+					"",
+					"foo@v1 .",
+					"",
+				},
+				want: "line 6",
+			},
+			"unknown entry": {
+				content: []string{
+					"foo@v1 bar",
+					"",
+					"foo@v1 .",
+					"hello@v2 foo@v1",
+				},
+				want: "unknown entry",
+			},
+			"unknown parent": {
+				content: []string{
+					"foo@v1 bar",
+					"",
+					"foo@v1 hello@v2",
+				},
+				want: "unknown parent",
+			},
+			"duplicate direct edge": {
+				content: []string{
+					"foo@v1 bar",
+					"",
+					"foo@v1 .",
+					"foo@v1 .",
+				},
+				want: "duplicate edge",
+			},
+			"duplicate parent edge": {
+				content: []string{
+					"foo@v1 bar",
+					"hello@v2 world",
+					"",
+					"foo@v1 .",
+					"hello@v2 foo@v1",
+					"hello@v2 foo@v1",
+				},
+				want: "duplicate parent",
+			},
+			"unused entry": {
+				content: []string{
+					"foo@v1 bar",
+					"hello@v2 world",
+					"",
+					"foo@v1 .",
+				},
+				want: "not used",
+			},
+			"cycle": {
+				content: []string{
+					"foo@v1 bar",
+					"hello@v2 world",
+					"",
+					"foo@v1 .",
+					"foo@v1 hello@v2",
+					"hello@v2 foo@v1",
+				},
+				want: "depends on itself",
+			},
+			"root entry": {
+				content: []string{
+					". bar",
+				},
+				want: ErrSyntax.Error(),
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := decodeV2(tt.content)
+				if err == nil {
+					t.Fatal("Unexpected success")
+				}
+
+				if got, want := err.Error(), tt.want; !strings.Contains(got, want) {
+					t.Errorf("Incorrect error (got %q, want %q)", got, want)
+				}
+			})
+		}
+	})
+}
+
+func TestEncodeV2(t *testing.T) {
+	t.Run("Valid examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			content []Entry
+			want    string
+		}
+
+		testCases := map[string]TestCase{
+			"no checksums": {
+				content: []Entry{},
+				want:    ``,
+			},
+			"no graph": {
+				content: []Entry{
+					{
+						Checksum: "bar",
+						ID:       []string{"foo", "v1"},
+					},
+				},
+				want: `foo@v1 bar
+`,
+			},
+			"graph": {
+				content: []Entry{
+					{
+						Checksum: "world",
+						Direct:   true,
+						ID:       []string{"hello", "v2"},
+						Parents:  [][]string{{"foo", "v1"}},
+					},
+					{
+						Checksum: "bar",
+						Direct:   true,
+						ID:       []string{"foo", "v1"},
+					},
+				},
+				want: `foo@v1 bar
+hello@v2 world
+
+foo@v1 .
+hello@v2 .
+hello@v2 foo@v1
+`,
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := encodeV2(tt.content)
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if want := tt.want; got != want {
+					t.Fatalf("Incorrect result (got %q, want %q)", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid examples", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string][]Entry{
+			"invalid checksum": {
+				{
+					ID:       []string{"anything"},
+					Checksum: "Hello world!",
+				},
+			},
+			"root entry": {
+				{
+					ID:       []string{rootID},
+					Checksum: "anything",
+				},
+			},
+			"unused entry": {
+				{
+					ID:       []string{"foo"},
+					Checksum: "anything",
+					Direct:   true,
+				},
+				{
+					ID:       []string{"bar"},
+					Checksum: "anything",
+				},
+			},
+			"unknown parent": {
+				{
+					ID:       []string{"foo"},
+					Checksum: "anything",
+					Parents:  [][]string{{"bar"}},
+				},
+			},
+			"self parent": {
+				{
+					ID:       []string{"foo"},
+					Checksum: "anything",
+					Direct:   true,
+					Parents:  [][]string{{"foo"}},
+				},
+			},
+		}
+
+		for name, entries := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := encodeV2(entries)
+				if err == nil {
+					t.Fatal("Unexpected success")
+				}
+
+				if !errors.Is(err, ErrCorrupted) {
+					t.Errorf("Incorrect error (got %v, want %v)", err, ErrCorrupted)
+				}
+			})
+		}
+	})
+}
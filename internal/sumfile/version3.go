@@ -0,0 +1,80 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumfile
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+func decodeV3(lines []string) ([]Entry, error) {
+	checksums := lines
+	if i := slices.Index(lines, ""); i != -1 {
+		checksums = lines[:i]
+	}
+
+	stripped := slices.Clone(lines)
+	commits := make([]string, len(checksums))
+	for i, line := range checksums {
+		// split "line" into "id[@id..]" "sum" ["commit"]
+		parts := strings.Split(line, " ")
+		switch {
+		case len(parts) == 3 && parts[2] != "":
+			stripped[i] = parts[0] + " " + parts[1]
+			commits[i] = parts[2]
+		case len(parts) != 2:
+			return nil, syntaxError(i + 3)
+		}
+	}
+
+	entries, err := decodeV2(stripped)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, commit := range commits {
+		entries[i].Commit = commit
+	}
+
+	return entries, nil
+}
+
+func encodeV3(entries []Entry) (string, error) {
+	if err := validV3(entries); err != nil {
+		return "", errors.Join(ErrCorrupted, err)
+	}
+
+	checksums := encodeChecksums(entries, true)
+	if !HasGraph(entries) {
+		return checksums, nil
+	}
+
+	return checksums + "\n" + encodeGraph(entries), nil
+}
+
+func validV3(entries []Entry) error {
+	if err := validV2(entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if strings.ContainsAny(entry.Commit, whitespace) {
+			return ErrSyntax
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,301 @@
+// Copyright 2026 Eric Cornelissen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestVersion3(t *testing.T) {
+	t.Parallel()
+
+	correct := func(entries []Entry) bool {
+		if err := validV3(entries); err != nil {
+			return true
+		}
+
+		encoded, _ := encodeV3(entries)
+		lines := strings.Split(encoded, "\n")
+
+		decoded, err := decodeV3(lines[:len(lines)-1])
+		if err != nil {
+			return true // Ignore errors, tested separately
+		}
+
+		return SetEqual(Stored(Version3, decoded), Stored(Version3, entries))
+	}
+
+	if err := quick.Check(correct, nil); err != nil {
+		t.Errorf("decode(encode(x)) != x for: %v", err)
+	}
+
+	deterministic := func(entries []Entry) bool {
+		got1, err1 := encodeV3(entries)
+		got2, err2 := encodeV3(entries)
+		return got1 == got2 && ((err1 == nil) == (err2 == nil))
+	}
+
+	if err := quick.Check(deterministic, nil); err != nil {
+		t.Errorf("encode(x) != encode(x) for: %v", err)
+	}
+}
+
+func TestDecodeV3(t *testing.T) {
+	t.Run("Valid examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			content []string
+			want    []Entry
+		}
+
+		testCases := map[string]TestCase{
+			"no checksums": {
+				content: []string{},
+				want:    []Entry{},
+			},
+			"no commit": {
+				content: []string{
+					"foo@v1 bar",
+				},
+				want: []Entry{
+					{
+						Checksum: "bar",
+						ID:       []string{"foo", "v1"},
+					},
+				},
+			},
+			"commit": {
+				content: []string{
+					"foo@v1 bar cafe",
+				},
+				want: []Entry{
+					{
+						Checksum: "bar",
+						Commit:   "cafe",
+						ID:       []string{"foo", "v1"},
+					},
+				},
+			},
+			"commit and graph": {
+				content: []string{
+					"foo@v1 bar cafe",
+					"hello@v2 world",
+					"",
+					"foo@v1 .",
+					"hello@v2 foo@v1",
+				},
+				want: []Entry{
+					{
+						Checksum: "bar",
+						Commit:   "cafe",
+						Direct:   true,
+						ID:       []string{"foo", "v1"},
+					},
+					{
+						Checksum: "world",
+						ID:       []string{"hello", "v2"},
+						Parents:  [][]string{{"foo", "v1"}},
+					},
+				},
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := decodeV3(tt.content)
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if !SetEqual(got, tt.want) {
+					t.Errorf("Incorrect result (got %+v, want %+v)", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			content []string
+			want    string
+		}
+
+		testCases := map[string]TestCase{
+			"missing checksum": {
+				content: []string{
+					"foo@v1",
+				},
+				want: "line 3",
+			},
+			"empty commit": {
+				content: []string{
+					"foo@v1 bar ",
+				},
+				want: "line 3",
+			},
+			"too many parts": {
+				content: []string{
+					"foo@v1 bar",
+					"hello@v2 world cafe beef",
+				},
+				want: "line 4",
+			},
+			"syntax error in graph": {
+				content: []string{
+					"foo@v1 bar cafe",
+					"",
+					"foo@v1",
+				},
+				want: "line 5",
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := decodeV3(tt.content)
+				if err == nil {
+					t.Fatal("Unexpected success")
+				}
+
+				if got, want := err.Error(), tt.want; !strings.Contains(got, want) {
+					t.Errorf("Incorrect error (got %q, want %q)", got, want)
+				}
+			})
+		}
+	})
+}
+
+func TestEncodeV3(t *testing.T) {
+	t.Run("Valid examples", func(t *testing.T) {
+		t.Parallel()
+
+		type TestCase struct {
+			content []Entry
+			want    string
+		}
+
+		testCases := map[string]TestCase{
+			"no checksums": {
+				content: []Entry{},
+				want:    ``,
+			},
+			"commits": {
+				content: []Entry{
+					{
+						Checksum: "world",
+						ID:       []string{"hello", "v2"},
+					},
+					{
+						Checksum: "bar",
+						Commit:   "cafe",
+						ID:       []string{"foo", "v1"},
+					},
+				},
+				want: `foo@v1 bar cafe
+hello@v2 world
+`,
+			},
+			"graph": {
+				content: []Entry{
+					{
+						Checksum: "world",
+						Commit:   "beef",
+						ID:       []string{"hello", "v2"},
+						Parents:  [][]string{{"foo", "v1"}},
+					},
+					{
+						Checksum: "bar",
+						Commit:   "cafe",
+						Direct:   true,
+						ID:       []string{"foo", "v1"},
+					},
+				},
+				want: `foo@v1 bar cafe
+hello@v2 world beef
+
+foo@v1 .
+hello@v2 foo@v1
+`,
+			},
+		}
+
+		for name, tt := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := encodeV3(tt.content)
+				if err != nil {
+					t.Fatalf("Unexpected error: %+v", err)
+				}
+
+				if want := tt.want; got != want {
+					t.Fatalf("Incorrect result (got %q, want %q)", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid examples", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string][]Entry{
+			"invalid checksum": {
+				{
+					ID:       []string{"anything"},
+					Checksum: "Hello world!",
+				},
+			},
+			"invalid commit": {
+				{
+					ID:       []string{"anything"},
+					Checksum: "anything",
+					Commit:   "Hello world!",
+				},
+			},
+			"unknown parent": {
+				{
+					ID:       []string{"foo"},
+					Checksum: "anything",
+					Parents:  [][]string{{"bar"}},
+				},
+			},
+		}
+
+		for name, entries := range testCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := encodeV3(entries)
+				if err == nil {
+					t.Fatal("Unexpected success")
+				}
+
+				if !errors.Is(err, ErrCorrupted) {
+					t.Errorf("Incorrect error (got %v, want %v)", err, ErrCorrupted)
+				}
+			})
+		}
+	})
+}
@@ -21,6 +21,15 @@ const (
 	// Version1 is the first checksum file version.
 	Version1 Version = 1 + iota
 
+	// Version2 is the second checksum file version. It extends Version1 with the
+	// dependency graph, which ghasum always records but accepts to be missing
+	// when it is parsed.
+	Version2
+
+	// Version3 is the third checksum file version. It extends Version2 with the
+	// commit for which each checksum was computed.
+	Version3
+
 	// VersionLatest has the value of the latest checksum file Version.
-	VersionLatest = Version1
+	VersionLatest = Version3
 )